@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	_ "time/tzdata" // User time zones are validated without relying on the host's zoneinfo
 
 	"github.com/KBM2795/DevArena-Backend/internal/config"
 	"github.com/KBM2795/DevArena-Backend/internal/db"
@@ -23,7 +24,10 @@ func main() {
 	defer db.Close()
 
 	// 3. Initialize and Start Server
-	srv := server.NewServer(cfg, db)
+	srv, err := server.NewServer(cfg, db)
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
 	if err := srv.Run(); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
@@ -0,0 +1,31 @@
+// Package keys parses the public keys session tokens are verified with. It has no
+// dependencies beyond the standard library so configuration can check keys
+// without pulling in the HTTP middleware.
+package keys
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// ParseRSAPublicKeyPEM parses a PKIX RSA public key in PEM form, the format of
+// Clerk's PEM public key
+func ParseRSAPublicKeyPEM(pemPublicKey string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemPublicKey))
+	if block == nil {
+		return nil, fmt.Errorf("failed to parse PEM block containing the public key")
+	}
+
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	rsaPublicKey, ok := pub.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("not an RSA public key")
+	}
+	return rsaPublicKey, nil
+}
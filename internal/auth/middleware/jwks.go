@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// jwksFetchTimeout bounds a single JWKS download when no HTTP client is given
+	jwksFetchTimeout = 10 * time.Second
+	// maxJWKSSize caps the JWKS document read from the network
+	maxJWKSSize = 1 << 20
+)
+
+// ErrUnknownKey is returned when no signing key matches a token's kid
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// JWKS fetches the RSA signing keys published at a JWKS URL, such as
+// https://<clerk-frontend-api>/.well-known/jwks.json, and caches them by key ID
+type JWKS struct {
+	url        string
+	client     *http.Client
+	cacheTTL   time.Duration
+	minRefresh time.Duration
+	now        func() time.Time // Clock for cache expiry and rate limiting
+
+	fetchMu     sync.Mutex // Serializes fetches
+	mu          sync.Mutex // Guards the fields below
+	keys        map[string]*rsa.PublicKey
+	fetchedAt   time.Time
+	lastAttempt time.Time
+}
+
+// NewJWKS creates a key set for url. Keys are refetched once they are older than
+// cacheTTL, or when a token names a key ID that is not cached; refetches happen
+// at most once per minRefresh so unknown key IDs cannot flood the endpoint. A nil
+// client uses a default client with a short timeout.
+func NewJWKS(url string, client *http.Client, cacheTTL, minRefresh time.Duration) *JWKS {
+	if client == nil {
+		client = &http.Client{Timeout: jwksFetchTimeout}
+	}
+	return &JWKS{
+		url:        url,
+		client:     client,
+		cacheTTL:   cacheTTL,
+		minRefresh: minRefresh,
+		now:        time.Now,
+		keys:       map[string]*rsa.PublicKey{},
+	}
+}
+
+// Key returns the signing key with the given key ID. Cached keys are returned
+// right away; once the cache has expired they are refreshed in the background,
+// so an unreachable JWKS endpoint never delays requests for known keys. Only a
+// key ID that is not cached waits for a fetch.
+func (j *JWKS) Key(kid string) (*rsa.PublicKey, error) {
+	if key, ok, stale := j.cached(kid); ok {
+		if stale {
+			j.refreshInBackground()
+		}
+		return key, nil
+	}
+
+	j.fetchMu.Lock()
+	defer j.fetchMu.Unlock()
+
+	// Another request may have fetched the key while we waited
+	if key, ok, _ := j.cached(kid); ok {
+		return key, nil
+	}
+
+	if !j.startAttempt() {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	keys, err := j.refresh()
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	return key, nil
+}
+
+// refreshInBackground refetches the key set unless a fetch is already running
+// or the last attempt was too recent. Failures keep the cached keys in use.
+func (j *JWKS) refreshInBackground() {
+	if !j.fetchMu.TryLock() {
+		return
+	}
+	if !j.startAttempt() {
+		j.fetchMu.Unlock()
+		return
+	}
+
+	go func() {
+		defer j.fetchMu.Unlock()
+		if _, err := j.refresh(); err != nil {
+			log.Printf("Failed to refresh JWKS, keeping cached keys: %v", err)
+		}
+	}()
+}
+
+// startAttempt records a fetch attempt, reporting false when the previous one
+// was less than minRefresh ago. The caller must hold fetchMu.
+func (j *JWKS) startAttempt() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	now := j.now()
+	if !j.lastAttempt.IsZero() && now.Sub(j.lastAttempt) < j.minRefresh {
+		return false
+	}
+	j.lastAttempt = now
+	return true
+}
+
+// refresh fetches the key set and replaces the cache with it. The caller must hold fetchMu.
+func (j *JWKS) refresh() (map[string]*rsa.PublicKey, error) {
+	keys, err := j.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	j.keys, j.fetchedAt = keys, j.now()
+	j.mu.Unlock()
+	return keys, nil
+}
+
+// cached looks a key up in the cache and reports whether the cache has expired
+func (j *JWKS) cached(kid string) (key *rsa.PublicKey, ok, stale bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	key, ok = j.keys[kid]
+	stale = j.cacheTTL > 0 && j.now().Sub(j.fetchedAt) > j.cacheTTL
+	return key, ok, stale
+}
+
+// jwk is a single JSON Web Key; only RSA keys are used
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetch downloads the key set and returns its RSA signing keys by key ID
+func (j *JWKS) fetch() (map[string]*rsa.PublicKey, error) {
+	resp, err := j.client.Get(j.url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch JWKS: unexpected status %d", resp.StatusCode)
+	}
+
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range doc.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") || (k.Alg != "" && k.Alg != "RS256") {
+			continue
+		}
+		// One malformed key must not lock out tokens signed with the others
+		key, err := parseRSAJWK(k)
+		if err != nil {
+			log.Printf("Skipping JWKS key %q: %v", k.Kid, err)
+			continue
+		}
+		keys[k.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no RSA signing keys")
+	}
+	return keys, nil
+}
+
+// parseRSAJWK builds an RSA public key from its base64url modulus and exponent
+func parseRSAJWK(k jwk) (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if len(n) == 0 || !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid key parameters")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+}
@@ -0,0 +1,350 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testClock is a manually advanced clock
+type testClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newTestClock() *testClock {
+	return &testClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// publicKeyPEM encodes key's public half the way Clerk shows the PEM public key
+func publicKeyPEM(t *testing.T, key *rsa.PrivateKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// signToken signs claims with key, naming kid in the header when it is set
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.Claims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// jwksServer is a stand-in for Clerk's JWKS endpoint
+type jwksServer struct {
+	*httptest.Server
+	requests atomic.Int32
+
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	failing bool
+	block   chan struct{} // When set, requests wait for it to be closed
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+	s := &jwksServer{keys: map[string]*rsa.PublicKey{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *jwksServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.requests.Add(1)
+
+	s.mu.Lock()
+	block, failing := s.block, s.failing
+	var doc struct {
+		Keys []jwk `json:"keys"`
+	}
+	for kid, key := range s.keys {
+		doc.Keys = append(doc.Keys, jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	s.mu.Unlock()
+
+	if block != nil {
+		<-block
+	}
+	if failing {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	json.NewEncoder(w).Encode(doc)
+}
+
+// setKeys replaces the published key set
+func (s *jwksServer) setKeys(keys map[string]*rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *jwksServer) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failing = failing
+}
+
+func (s *jwksServer) setBlock(block chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.block = block
+}
+
+func newTestJWKS(server *jwksServer, clock *testClock) *JWKS {
+	j := NewJWKS(server.URL, server.Client(), time.Hour, time.Minute)
+	j.now = clock.Now
+	return j
+}
+
+func TestJWKSKeyRotation(t *testing.T) {
+	server := newJWKSServer(t)
+	clock := newTestClock()
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	server.setKeys(map[string]*rsa.PublicKey{"old": &oldKey.PublicKey})
+	jwks := newTestJWKS(server, clock)
+
+	key, err := jwks.Key("old")
+	if err != nil || !key.Equal(&oldKey.PublicKey) {
+		t.Fatalf("Key(old) = %v, %v; want the old key", key, err)
+	}
+
+	// Clerk rotates to a new key; tokens signed with it name the new kid
+	server.setKeys(map[string]*rsa.PublicKey{"new": &newKey.PublicKey})
+	clock.Advance(time.Minute)
+
+	key, err = jwks.Key("new")
+	if err != nil || !key.Equal(&newKey.PublicKey) {
+		t.Fatalf("Key(new) = %v, %v; want the new key", key, err)
+	}
+	if n := server.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+
+	// The rotated-out key is gone from the refreshed set
+	clock.Advance(time.Minute)
+	if _, err := jwks.Key("old"); !errors.Is(err, ErrUnknownKey) {
+		t.Errorf("Key(old) after rotation error = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestJWKSRateLimitsUnknownKeys(t *testing.T) {
+	server := newJWKSServer(t)
+	clock := newTestClock()
+	key := newTestKey(t)
+	server.setKeys(map[string]*rsa.PublicKey{"known": &key.PublicKey})
+	jwks := newTestJWKS(server, clock)
+
+	for range 5 {
+		if _, err := jwks.Key("unknown"); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("Key(unknown) error = %v, want ErrUnknownKey", err)
+		}
+	}
+	if n := server.requests.Load(); n != 1 {
+		t.Fatalf("JWKS fetched %d times for repeated unknown kids, want 1", n)
+	}
+
+	// Known keys are still served from the cache in between
+	if _, err := jwks.Key("known"); err != nil {
+		t.Fatalf("Key(known): %v", err)
+	}
+
+	clock.Advance(time.Minute)
+	if _, err := jwks.Key("unknown"); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Key(unknown) error = %v, want ErrUnknownKey", err)
+	}
+	if n := server.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times after minRefresh passed, want 2", n)
+	}
+}
+
+func TestJWKSServesStaleKeyWhenFetchFails(t *testing.T) {
+	server := newJWKSServer(t)
+	clock := newTestClock()
+	key := newTestKey(t)
+	server.setKeys(map[string]*rsa.PublicKey{"kid": &key.PublicKey})
+	jwks := newTestJWKS(server, clock)
+
+	if _, err := jwks.Key("kid"); err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+
+	// The endpoint goes down and hangs; the expired cache must not make callers wait
+	release := make(chan struct{})
+	server.setBlock(release)
+	server.setFailing(true)
+	clock.Advance(2 * time.Hour)
+
+	done := make(chan error, 1)
+	go func() {
+		got, err := jwks.Key("kid")
+		if err == nil && !got.Equal(&key.PublicKey) {
+			err = errors.New("wrong key")
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Key with stale cache: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Key with stale cache waited on the JWKS fetch")
+	}
+
+	// Let the background refresh fail, then check the stale key is still served
+	close(release)
+	waitFor(t, func() bool { return server.requests.Load() == 2 && jwks.fetchMu.TryLock() })
+	jwks.fetchMu.Unlock()
+
+	if got, err := jwks.Key("kid"); err != nil || !got.Equal(&key.PublicKey) {
+		t.Errorf("Key after failed refresh = %v, %v; want the cached key", got, err)
+	}
+	if n := server.requests.Load(); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2 (refreshes are rate limited)", n)
+	}
+}
+
+func TestJWKSBackgroundRefreshReplacesKeys(t *testing.T) {
+	server := newJWKSServer(t)
+	clock := newTestClock()
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	server.setKeys(map[string]*rsa.PublicKey{"kid": &oldKey.PublicKey})
+	jwks := newTestJWKS(server, clock)
+
+	if _, err := jwks.Key("kid"); err != nil {
+		t.Fatalf("Key: %v", err)
+	}
+
+	server.setKeys(map[string]*rsa.PublicKey{"kid": &newKey.PublicKey})
+	clock.Advance(2 * time.Hour)
+
+	// The stale key is served while the refresh runs
+	if got, err := jwks.Key("kid"); err != nil || !got.Equal(&oldKey.PublicKey) {
+		t.Fatalf("Key with stale cache = %v, %v; want the old key", got, err)
+	}
+	waitFor(t, func() bool {
+		got, err := jwks.Key("kid")
+		return err == nil && got.Equal(&newKey.PublicKey)
+	})
+}
+
+func TestJWTMiddlewarePEMFallback(t *testing.T) {
+	server := newJWKSServer(t)
+	clock := newTestClock()
+	jwksKey, pemKey, otherKey := newTestKey(t), newTestKey(t), newTestKey(t)
+	server.setKeys(map[string]*rsa.PublicKey{"jwks": &jwksKey.PublicKey})
+
+	m, err := NewJWTMiddleware(publicKeyPEM(t, pemKey), nil, newTestJWKS(server, clock), TokenValidation{Now: clock.Now})
+	if err != nil {
+		t.Fatalf("NewJWTMiddleware: %v", err)
+	}
+	claims := func() *ClerkClaims {
+		return &ClerkClaims{RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user_123",
+			ExpiresAt: jwt.NewNumericDate(clock.Now().Add(time.Minute)),
+		}}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		key     *rsa.PrivateKey
+		kid     string
+		wantErr bool
+	}{
+		{"key from the JWKS", jwksKey, "jwks", false},
+		{"unknown kid signed with the PEM key", pemKey, "unknown", false},
+		{"no kid signed with the PEM key", pemKey, "", false},
+		{"unknown kid signed with another key", otherKey, "unknown", true},
+		{"JWKS kid signed with another key", otherKey, "jwks", true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			clock.Advance(time.Minute) // Let every case refetch
+			_, err := m.validateToken(signToken(t, tt.key, tt.kid, claims()))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("JWKS unavailable", func(t *testing.T) {
+		server.setFailing(true)
+		clock.Advance(time.Minute)
+		if _, err := m.validateToken(signToken(t, pemKey, "rotated", claims())); err != nil {
+			t.Errorf("validateToken with the PEM key: %v", err)
+		}
+	})
+
+	t.Run("JWKS only", func(t *testing.T) {
+		server.setFailing(false)
+		jwksOnly, err := NewJWTMiddleware("", nil, newTestJWKS(server, clock), TokenValidation{Now: clock.Now})
+		if err != nil {
+			t.Fatalf("NewJWTMiddleware: %v", err)
+		}
+		if _, err := jwksOnly.validateToken(signToken(t, pemKey, "unknown", claims())); !errors.Is(err, ErrUnknownKey) {
+			t.Errorf("validateToken error = %v, want ErrUnknownKey", err)
+		}
+	})
+}
+
+// waitFor polls cond until it holds or the test times out
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
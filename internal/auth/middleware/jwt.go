@@ -2,13 +2,14 @@ package middleware
 
 import (
 	"crypto/rsa"
-	"crypto/x509"
-	"encoding/pem"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/KBM2795/DevArena-Backend/internal/auth/keys"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -20,6 +21,10 @@ type ClerkClaims struct {
 	AuthorizedParty string `json:"azp,omitempty"`
 	Email           string `json:"email,omitempty"`
 	Status          string `json:"sts,omitempty"` // For org membership status
+
+	// Metadata is an optional custom session claim, configured in Clerk as
+	// {"metadata": "{{user.public_metadata}}"}
+	Metadata map[string]any `json:"metadata,omitempty"`
 }
 
 // ContextKey type for context values
@@ -34,57 +39,73 @@ const (
 	ClaimsKey ContextKey = "claims"
 )
 
+// TokenValidation configures the registered claim checks applied to session tokens
+type TokenValidation struct {
+	Leeway   time.Duration    // Clock skew tolerated when checking exp and nbf
+	Issuer   string           // Required iss claim, Clerk's frontend API URL; empty skips the check
+	Audience string           // Required aud claim; empty skips the check
+	Now      func() time.Time // Clock the time-based claims are checked against, time.Now when nil
+}
+
 // JWTMiddleware handles Clerk JWT verification
 type JWTMiddleware struct {
-	publicKey         *rsa.PublicKey
+	publicKey         *rsa.PublicKey // Static key, nil when only the JWKS is used
+	jwks              *JWKS          // Rotating keys, nil when only the static key is used
 	authorizedParties []string
+	parser            *jwt.Parser
 }
 
 // NewJWTMiddleware creates a new JWT middleware instance
-// pemPublicKey: Your Clerk PEM public key (from CLERK_PEM_PUBLIC_KEY env var)
+// pemPublicKey: Your Clerk PEM public key (from CLERK_PEM_PUBLIC_KEY env var), optional when jwks is set
 // authorizedParties: List of permitted origins (e.g., ["http://localhost:3000", "https://devarena.dev"])
-func NewJWTMiddleware(pemPublicKey string, authorizedParties []string) (*JWTMiddleware, error) {
-	// Parse the PEM public key
-	block, _ := pem.Decode([]byte(pemPublicKey))
-	if block == nil {
-		return nil, fmt.Errorf("failed to parse PEM block containing the public key")
+// jwks: Clerk's JWKS, optional; tokens are verified with the key named by their kid
+// header, falling back to the PEM key when the JWKS cannot provide it
+// validation: leeway, expected issuer and audience, and the clock used for exp and nbf
+func NewJWTMiddleware(pemPublicKey string, authorizedParties []string, jwks *JWKS, validation TokenValidation) (*JWTMiddleware, error) {
+	m := &JWTMiddleware{
+		jwks:              jwks,
+		authorizedParties: authorizedParties,
+		parser:            newTokenParser(validation),
+	}
+	if pemPublicKey == "" && jwks != nil {
+		return m, nil
 	}
 
-	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	rsaPublicKey, err := keys.ParseRSAPublicKeyPEM(pemPublicKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse public key: %w", err)
+		return nil, err
 	}
 
-	rsaPublicKey, ok := pub.(*rsa.PublicKey)
-	if !ok {
-		return nil, fmt.Errorf("not an RSA public key")
+	m.publicKey = rsaPublicKey
+	return m, nil
+}
+
+// newTokenParser builds the parser that verifies a token's signature and its
+// exp, nbf, iss and aud claims
+func newTokenParser(validation TokenValidation) *jwt.Parser {
+	now := validation.Now
+	if now == nil {
+		now = time.Now
 	}
 
-	return &JWTMiddleware{
-		publicKey:         rsaPublicKey,
-		authorizedParties: authorizedParties,
-	}, nil
+	opts := []jwt.ParserOption{
+		jwt.WithLeeway(validation.Leeway),
+		jwt.WithTimeFunc(now),
+	}
+	// Clerk's iss has no trailing slash, so a configured one must not prevent a match
+	if issuer := strings.TrimSuffix(validation.Issuer, "/"); issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if validation.Audience != "" {
+		opts = append(opts, jwt.WithAudience(validation.Audience))
+	}
+	return jwt.NewParser(opts...)
 }
 
 // Authenticate returns a Gin middleware function for JWT authentication
 func (m *JWTMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var tokenString string
-
-		// Try to get token from Authorization header first (cross-origin)
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
-				tokenString = parts[1]
-			}
-		}
-
-		// If no Authorization header, try __session cookie (same-origin)
-		if tokenString == "" {
-			tokenString, _ = c.Cookie("__session")
-		}
-
+		tokenString := extractToken(c)
 		if tokenString == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
 				"error": "No session token found. Provide Authorization header or __session cookie.",
@@ -95,9 +116,7 @@ func (m *JWTMiddleware) Authenticate() gin.HandlerFunc {
 		// Parse and validate the token
 		claims, err := m.validateToken(tokenString)
 		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
-				"error": fmt.Sprintf("Invalid token: %v", err),
-			})
+			rejectToken(c, err)
 			return
 		}
 
@@ -111,15 +130,76 @@ func (m *JWTMiddleware) Authenticate() gin.HandlerFunc {
 	}
 }
 
-// validateToken validates the JWT token using Clerk's PEM public key
+// OptionalAuthenticate returns a Gin middleware that identifies the caller when a
+// session token is present and otherwise lets the request through anonymously.
+// A token that is present but malformed or expired is rejected like in
+// Authenticate rather than silently ignored, so clients know to refresh it
+// instead of getting unpersonalized results.
+func (m *JWTMiddleware) OptionalAuthenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := extractToken(c)
+		if tokenString == "" {
+			c.Next()
+			return
+		}
+
+		claims, err := m.validateToken(tokenString)
+		if err != nil {
+			rejectToken(c, err)
+			return
+		}
+
+		c.Set(string(UserIDKey), claims.Subject)
+		c.Set(string(SessionIDKey), claims.SessionID)
+		c.Set(string(ClaimsKey), claims)
+
+		c.Next()
+	}
+}
+
+// rejectToken aborts with 401 for a token that failed validation. The code field
+// and the WWW-Authenticate header (RFC 6750) tell clients whether refreshing the
+// session token will help.
+func rejectToken(c *gin.Context, err error) {
+	code := "invalid_token"
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		code = "token_expired"
+	case errors.Is(err, jwt.ErrTokenNotValidYet):
+		code = "token_not_yet_valid"
+	}
+
+	c.Header("WWW-Authenticate", fmt.Sprintf(`Bearer error="invalid_token", error_description=%q`, code))
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"error": fmt.Sprintf("Invalid token: %v", err),
+		"code":  code,
+	})
+}
+
+// extractToken reads the session token from the Authorization header (cross-origin)
+// or, failing that, from the __session cookie (same-origin)
+func extractToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && strings.ToLower(parts[0]) == "bearer" {
+			return parts[1]
+		}
+	}
+
+	tokenString, _ := c.Cookie("__session")
+	return tokenString
+}
+
+// validateToken validates the JWT token's signature and claims
 func (m *JWTMiddleware) validateToken(tokenString string) (*ClerkClaims, error) {
-	// Parse the token with claims
-	token, err := jwt.ParseWithClaims(tokenString, &ClerkClaims{}, func(token *jwt.Token) (interface{}, error) {
+	// Parse the token with claims; the parser also checks exp, nbf, iss and aud
+	token, err := m.parser.ParseWithClaims(tokenString, &ClerkClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Validate the algorithm is RS256
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return m.publicKey, nil
+		return m.verificationKey(token)
 	})
 
 	if err != nil {
@@ -135,15 +215,6 @@ func (m *JWTMiddleware) validateToken(tokenString string) (*ClerkClaims, error)
 		return nil, fmt.Errorf("failed to parse claims")
 	}
 
-	// Validate expiration and not-before claims
-	now := time.Now()
-	if claims.ExpiresAt != nil && claims.ExpiresAt.Time.Before(now) {
-		return nil, fmt.Errorf("token is expired")
-	}
-	if claims.NotBefore != nil && claims.NotBefore.Time.After(now) {
-		return nil, fmt.Errorf("token is not yet valid")
-	}
-
 	// Validate authorized party (azp) claim if present and if we have authorized parties configured
 	if claims.AuthorizedParty != "" && len(m.authorizedParties) > 0 {
 		isAuthorized := false
@@ -166,6 +237,25 @@ func (m *JWTMiddleware) validateToken(tokenString string) (*ClerkClaims, error)
 	return claims, nil
 }
 
+// verificationKey picks the key a token is verified with: the JWKS key named by
+// its kid header when available, otherwise the static PEM key
+func (m *JWTMiddleware) verificationKey(token *jwt.Token) (*rsa.PublicKey, error) {
+	if m.jwks != nil {
+		kid, _ := token.Header["kid"].(string)
+		key, err := m.jwks.Key(kid)
+		if err == nil {
+			return key, nil
+		}
+		if m.publicKey == nil {
+			return nil, err
+		}
+		if !errors.Is(err, ErrUnknownKey) {
+			log.Printf("Falling back to the PEM public key: %v", err)
+		}
+	}
+	return m.publicKey, nil
+}
+
 // GetUserID extracts the user ID from the Gin context
 func GetUserID(c *gin.Context) (string, bool) {
 	userID, exists := c.Get(string(UserIDKey))
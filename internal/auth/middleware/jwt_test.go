@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testIssuer = "https://clerk.devarena.dev"
+	testLeeway = 5 * time.Second
+)
+
+// authenticate runs token through the Authenticate middleware and returns the
+// response status, error code and headers
+func authenticate(t *testing.T, m *JWTMiddleware, token string) (int, string, http.Header) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", m.Authenticate(), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body struct {
+		Code string `json:"code"`
+	}
+	if w.Code != http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decoding response %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code, body.Code, w.Header()
+}
+
+func TestTokenValidation(t *testing.T) {
+	key := newTestKey(t)
+	clock := newTestClock()
+	now := clock.Now()
+
+	m, err := NewJWTMiddleware(publicKeyPEM(t, key), nil, nil, TokenValidation{
+		Leeway:   testLeeway,
+		Issuer:   testIssuer,
+		Audience: "devarena-api",
+		Now:      clock.Now,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTMiddleware: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		claims   func(*jwt.RegisteredClaims)
+		wantCode string // Empty when the token is accepted
+	}{
+		{"valid", func(c *jwt.RegisteredClaims) {}, ""},
+		{"expired within leeway", func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-testLeeway + time.Second))
+		}, ""},
+		{"expired beyond leeway", func(c *jwt.RegisteredClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(now.Add(-testLeeway - time.Second))
+		}, "token_expired"},
+		{"not yet valid within leeway", func(c *jwt.RegisteredClaims) {
+			c.NotBefore = jwt.NewNumericDate(now.Add(testLeeway - time.Second))
+		}, ""},
+		{"not yet valid beyond leeway", func(c *jwt.RegisteredClaims) {
+			c.NotBefore = jwt.NewNumericDate(now.Add(testLeeway + time.Second))
+		}, "token_not_yet_valid"},
+		{"wrong issuer", func(c *jwt.RegisteredClaims) {
+			c.Issuer = "https://clerk.example.com"
+		}, "invalid_token"},
+		{"missing issuer", func(c *jwt.RegisteredClaims) {
+			c.Issuer = ""
+		}, "invalid_token"},
+		{"wrong audience", func(c *jwt.RegisteredClaims) {
+			c.Audience = jwt.ClaimStrings{"other-api"}
+		}, "invalid_token"},
+		{"missing audience", func(c *jwt.RegisteredClaims) {
+			c.Audience = nil
+		}, "invalid_token"},
+		{"one of several audiences", func(c *jwt.RegisteredClaims) {
+			c.Audience = jwt.ClaimStrings{"other-api", "devarena-api"}
+		}, ""},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.RegisteredClaims{
+				Subject:   "user_123",
+				Issuer:    testIssuer,
+				Audience:  jwt.ClaimStrings{"devarena-api"},
+				IssuedAt:  jwt.NewNumericDate(now),
+				ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			}
+			tt.claims(&claims)
+			token := signToken(t, key, "", &ClerkClaims{RegisteredClaims: claims})
+
+			status, code, header := authenticate(t, m, token)
+			if tt.wantCode == "" {
+				if status != http.StatusOK {
+					t.Fatalf("status = %d (code %q), want 200", status, code)
+				}
+				return
+			}
+			if status != http.StatusUnauthorized || code != tt.wantCode {
+				t.Errorf("status = %d, code = %q; want 401, %q", status, code, tt.wantCode)
+			}
+			if got := header.Get("WWW-Authenticate"); got != `Bearer error="invalid_token", error_description="`+tt.wantCode+`"` {
+				t.Errorf("WWW-Authenticate = %q", got)
+			}
+		})
+	}
+}
+
+func TestTokenValidationIssuerTrailingSlash(t *testing.T) {
+	key := newTestKey(t)
+	clock := newTestClock()
+
+	for _, tt := range []struct {
+		name        string
+		configured  string
+		tokenIssuer string
+		wantOK      bool
+	}{
+		{"configured without slash", testIssuer, testIssuer, true},
+		{"configured with slash", testIssuer + "/", testIssuer, true},
+		{"different host", testIssuer + "/", "https://clerk.example.com", false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewJWTMiddleware(publicKeyPEM(t, key), nil, nil, TokenValidation{Issuer: tt.configured, Now: clock.Now})
+			if err != nil {
+				t.Fatalf("NewJWTMiddleware: %v", err)
+			}
+			token := signToken(t, key, "", &ClerkClaims{RegisteredClaims: jwt.RegisteredClaims{
+				Subject:   "user_123",
+				Issuer:    tt.tokenIssuer,
+				ExpiresAt: jwt.NewNumericDate(clock.Now().Add(time.Minute)),
+			}})
+
+			_, err = m.validateToken(token)
+			if (err == nil) != tt.wantOK {
+				t.Errorf("validateToken error = %v, want ok %v", err, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestTokenValidationDefaults(t *testing.T) {
+	key := newTestKey(t)
+	m, err := NewJWTMiddleware(publicKeyPEM(t, key), nil, nil, TokenValidation{})
+	if err != nil {
+		t.Fatalf("NewJWTMiddleware: %v", err)
+	}
+
+	// Without an issuer or audience configured, neither is checked; the wall clock is used
+	valid := signToken(t, key, "", &ClerkClaims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "user_123",
+		Issuer:    "https://anything.example.com",
+		Audience:  jwt.ClaimStrings{"anything"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}})
+	if _, err := m.validateToken(valid); err != nil {
+		t.Errorf("validateToken: %v", err)
+	}
+
+	// Without leeway, a token that expired a second ago is rejected
+	expired := signToken(t, key, "", &ClerkClaims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "user_123",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Second)),
+	}})
+	if status, code, _ := authenticate(t, m, expired); status != http.StatusUnauthorized || code != "token_expired" {
+		t.Errorf("status = %d, code = %q; want 401, token_expired", status, code)
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// provisionedTTL is how long a subject known to have a users row skips the database check
+const provisionedTTL = 10 * time.Minute
+
+// knownSubjects remembers the Clerk user IDs confirmed to have a users row
+type knownSubjects struct {
+	mu        sync.Mutex
+	seen      map[string]time.Time // clerk user id -> time the row was last confirmed
+	lastSweep time.Time
+}
+
+// has reports whether subject was confirmed within provisionedTTL, forgetting it once expired
+func (k *knownSubjects) has(subject string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	at, ok := k.seen[subject]
+	if ok && time.Since(at) >= provisionedTTL {
+		delete(k.seen, subject)
+		return false
+	}
+	return ok
+}
+
+// add records subject as confirmed. Expired entries are swept once per TTL so
+// subjects that never come back do not accumulate.
+func (k *knownSubjects) add(subject string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	now := time.Now()
+	k.seen[subject] = now
+	if now.Sub(k.lastSweep) < provisionedTTL {
+		return
+	}
+	for s, at := range k.seen {
+		if now.Sub(at) >= provisionedTTL {
+			delete(k.seen, s)
+		}
+	}
+	k.lastSweep = now
+}
+
+// UserProvisioner creates a local user for an authenticated Clerk user if none exists
+type UserProvisioner interface {
+	EnsureUser(ctx context.Context, clerkUserID, email string) error
+}
+
+// ProvisionUser returns a Gin middleware that makes sure every authenticated
+// caller has a users row, creating a minimal one from the JWT claims when the
+// Clerk webhook has not synced the user yet. Anonymous requests pass through.
+// It must run after Authenticate or OptionalAuthenticate.
+func ProvisionUser(provisioner UserProvisioner) gin.HandlerFunc {
+	known := &knownSubjects{seen: map[string]time.Time{}}
+
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if known.has(claims.Subject) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+		if err := provisioner.EnsureUser(ctx, claims.Subject, claims.Email); err != nil {
+			log.Printf("Failed to provision user %s: %v", claims.Subject, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+			return
+		}
+		known.add(claims.Subject)
+
+		c.Next()
+	}
+}
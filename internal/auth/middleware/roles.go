@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+// RoleKey is the context key for the caller's role, set by RequireRole
+const RoleKey ContextKey = "role"
+
+// RoleStore reads and stores the roles of local users
+type RoleStore interface {
+	GetUserRole(ctx context.Context, clerkUserID string) (models.Role, error)
+	SetUserRole(ctx context.Context, clerkUserID string, role models.Role) error
+}
+
+// RoleAuthorizer resolves the caller's role and restricts routes to roles
+type RoleAuthorizer struct {
+	store        RoleStore
+	admins       []string
+	fromMetadata bool
+}
+
+// NewRoleAuthorizer creates a role authorizer.
+// admins: Clerk user IDs that are always admins, used to bootstrap the first admin
+// fromMetadata: trust a valid "role" in the token's metadata claim (Clerk public
+// metadata) over the stored role and store it
+func NewRoleAuthorizer(store RoleStore, admins []string, fromMetadata bool) *RoleAuthorizer {
+	return &RoleAuthorizer{
+		store:        store,
+		admins:       admins,
+		fromMetadata: fromMetadata,
+	}
+}
+
+// RequireRole returns a Gin middleware that only lets through callers with one of
+// the given roles. It must run after Authenticate and ProvisionUser.
+func (a *RoleAuthorizer) RequireRole(roles ...models.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := GetClaims(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+		role, err := a.resolve(ctx, claims)
+		if err != nil {
+			log.Printf("Failed to resolve role of user %s: %v", claims.Subject, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user role"})
+			return
+		}
+		c.Set(string(RoleKey), role)
+
+		if !slices.Contains(roles, role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// resolve returns the caller's role, syncing it from the token when configured
+func (a *RoleAuthorizer) resolve(ctx context.Context, claims *ClerkClaims) (models.Role, error) {
+	if slices.Contains(a.admins, claims.Subject) {
+		return models.RoleAdmin, nil
+	}
+	if a.fromMetadata {
+		if role := claims.MetadataRole(); role.IsValid() {
+			if err := a.store.SetUserRole(ctx, claims.Subject, role); err != nil {
+				return "", err
+			}
+			return role, nil
+		}
+	}
+	return a.store.GetUserRole(ctx, claims.Subject)
+}
+
+// MetadataRole returns the "role" key of the metadata claim, or "" when absent
+func (c *ClerkClaims) MetadataRole() models.Role {
+	role, _ := c.Metadata["role"].(string)
+	return models.Role(role)
+}
+
+// GetRole extracts the caller's role from the Gin context
+func GetRole(c *gin.Context) (models.Role, bool) {
+	role, exists := c.Get(string(RoleKey))
+	if !exists {
+		return "", false
+	}
+	return role.(models.Role), true
+}
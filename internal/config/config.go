@@ -2,15 +2,23 @@ package config
 
 import (
 	"log"
+	"slices"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Env      string   `mapstructure:"env"`
-	Server   Server   `mapstructure:"server"`
-	Database Database `mapstructure:"database"`
-	Clerk    Clerk    `mapstructure:"clerk"`
+	Env         string      `mapstructure:"env"`
+	Server      Server      `mapstructure:"server"`
+	Database    Database    `mapstructure:"database"`
+	Clerk       Clerk       `mapstructure:"clerk"`
+	Worker      Worker      `mapstructure:"worker"`
+	Reviewer    Reviewer    `mapstructure:"reviewer"`
+	Fetcher     Fetcher     `mapstructure:"fetcher"`
+	Leaderboard Leaderboard `mapstructure:"leaderboard"`
+	Streaks     Streaks     `mapstructure:"streaks"`
+	Roles       Roles       `mapstructure:"roles"`
 }
 
 type Server struct {
@@ -27,9 +35,59 @@ type Database struct {
 }
 
 type Clerk struct {
-	PEMPublicKey         string   `mapstructure:"pem_public_key"`
-	AuthorizedParties    []string `mapstructure:"authorized_parties"`
-	WebhookSigningSecret string   `mapstructure:"webhook_signing_secret"`
+	PEMPublicKey         string        `mapstructure:"pem_public_key"` // Optional when jwks_url is set; used when the JWKS cannot provide a key
+	AuthorizedParties    []string      `mapstructure:"authorized_parties"`
+	WebhookSigningSecret string        `mapstructure:"webhook_signing_secret"`
+	JWKSURL              string        `mapstructure:"jwks_url"`         // e.g. https://<frontend-api>/.well-known/jwks.json
+	JWKSCacheTTL         time.Duration `mapstructure:"jwks_cache_ttl"`   // How long fetched keys are trusted before refetching
+	JWKSMinRefresh       time.Duration `mapstructure:"jwks_min_refresh"` // Minimum time between fetches, e.g. for unknown key IDs
+	Leeway               time.Duration `mapstructure:"leeway"`           // Clock skew tolerated when checking token expiry
+	Issuer               string        `mapstructure:"issuer"`           // Expected iss claim, the frontend API URL, e.g. https://clerk.devarena.dev
+	Audience             string        `mapstructure:"audience"`         // Expected aud claim, only checked when set
+}
+
+// Worker configures the background review worker pool
+type Worker struct {
+	Concurrency  int           `mapstructure:"concurrency"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	MaxAttempts  int           `mapstructure:"max_attempts"`
+	BaseBackoff  time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff   time.Duration `mapstructure:"max_backoff"`
+	JobTimeout   time.Duration `mapstructure:"job_timeout"`
+	StaleAfter   time.Duration `mapstructure:"stale_after"` // Requeue reviews stuck in "reviewing" this long
+}
+
+// Reviewer selects and configures the code reviewer used by the workers
+type Reviewer struct {
+	Provider string        `mapstructure:"provider"` // "heuristic" (offline, default) or "http"
+	Endpoint string        `mapstructure:"endpoint"` // Review service URL for the http provider
+	APIKey   string        `mapstructure:"api_key"`
+	Timeout  time.Duration `mapstructure:"timeout"`
+}
+
+// Fetcher configures how submission repositories are checked out for review
+type Fetcher struct {
+	WorkDir   string        `mapstructure:"work_dir"` // Parent directory for workspaces, defaults to the OS temp dir
+	MaxSizeMB int           `mapstructure:"max_size_mb"`
+	MaxFiles  int           `mapstructure:"max_files"`
+	Timeout   time.Duration `mapstructure:"timeout"`
+}
+
+// Leaderboard configures the materialized leaderboard snapshots
+type Leaderboard struct {
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"` // 0 disables snapshots; reads fall back to live ranking
+}
+
+// Streaks configures how daily activity streaks are counted
+type Streaks struct {
+	CountAllSubmissions bool          `mapstructure:"count_all_submissions"` // Count any submission as activity, not only reviewed ones
+	ResetInterval       time.Duration `mapstructure:"reset_interval"`        // How often broken streaks are reset; 0 disables the job
+}
+
+// Roles configures how callers' roles are determined
+type Roles struct {
+	Admins       []string `mapstructure:"admins"`        // Clerk user IDs that are always admins; admin.clerk_user_ids is still read as an alias
+	FromMetadata bool     `mapstructure:"from_metadata"` // Take the role from the "role" key of the token's metadata claim
 }
 
 func LoadConfig() (*Config, error) {
@@ -40,6 +98,30 @@ func LoadConfig() (*Config, error) {
 
 	viper.AutomaticEnv()
 
+	viper.SetDefault("clerk.jwks_cache_ttl", "1h")
+	viper.SetDefault("clerk.jwks_min_refresh", "1m")
+	viper.SetDefault("clerk.leeway", "5s")
+
+	viper.SetDefault("worker.concurrency", 2)
+	viper.SetDefault("worker.poll_interval", "5s")
+	viper.SetDefault("worker.max_attempts", 3)
+	viper.SetDefault("worker.base_backoff", "30s")
+	viper.SetDefault("worker.max_backoff", "10m")
+	viper.SetDefault("worker.job_timeout", "5m")
+	viper.SetDefault("worker.stale_after", "15m")
+
+	viper.SetDefault("reviewer.provider", "heuristic")
+	viper.SetDefault("reviewer.timeout", "2m")
+
+	viper.SetDefault("fetcher.max_size_mb", 50)
+	viper.SetDefault("fetcher.max_files", 5000)
+	viper.SetDefault("fetcher.timeout", "2m")
+
+	viper.SetDefault("leaderboard.refresh_interval", "10m")
+
+	// Hourly so every time zone is reset shortly after its local midnight
+	viper.SetDefault("streaks.reset_interval", "1h")
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Printf("Error reading config file: %v", err)
 		return nil, err
@@ -51,5 +133,20 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	// The admin allowlist predates roles; keep honouring its old key so existing
+	// deployments do not lose their admins
+	if legacy := viper.GetStringSlice("admin.clerk_user_ids"); len(legacy) > 0 {
+		log.Printf("Config key admin.clerk_user_ids is deprecated, use roles.admins instead")
+		for _, id := range legacy {
+			if !slices.Contains(cfg.Roles.Admins, id) {
+				cfg.Roles.Admins = append(cfg.Roles.Admins, id)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/keys"
+)
+
+const (
+	// webhookSecretPrefix starts every Clerk (Svix) webhook signing secret
+	webhookSecretPrefix = "whsec_"
+	// maxClerkLeeway bounds the clock skew tolerance; Clerk session tokens only live a minute
+	maxClerkLeeway = time.Minute
+)
+
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid configuration:\n  - %s", strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the settings the server cannot start without and reports all
+// problems at once, so a broken deployment fails at startup instead of on the
+// first request
+func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if err := validatePort(c.Server.Port); err != nil {
+		problem("server.port: %v", err)
+	}
+
+	if c.Database.Host == "" {
+		problem("database.host is required")
+	}
+	if err := validatePort(c.Database.Port); err != nil {
+		problem("database.port: %v", err)
+	}
+	if c.Database.User == "" {
+		problem("database.user is required")
+	}
+	if c.Database.DBName == "" {
+		problem("database.dbname is required")
+	}
+	if c.Database.SSLMode != "" && !slices.Contains(sslModes, c.Database.SSLMode) {
+		problem("database.sslmode must be one of %s", strings.Join(sslModes, ", "))
+	}
+
+	switch {
+	case c.Clerk.PEMPublicKey != "":
+		if _, err := keys.ParseRSAPublicKeyPEM(c.Clerk.PEMPublicKey); err != nil {
+			problem("clerk.pem_public_key: %v", err)
+		}
+	case c.Clerk.JWKSURL == "":
+		problem("clerk.pem_public_key or clerk.jwks_url is required")
+	}
+	if c.Clerk.JWKSURL != "" {
+		if u, err := url.Parse(c.Clerk.JWKSURL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			problem("clerk.jwks_url must be an absolute http(s) URL")
+		}
+	}
+	if c.Clerk.Issuer != "" {
+		if u, err := url.Parse(c.Clerk.Issuer); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			problem("clerk.issuer must be an absolute http(s) URL")
+		}
+	}
+	if c.Clerk.Leeway < 0 || c.Clerk.Leeway > maxClerkLeeway {
+		problem("clerk.leeway must be between 0 and %s", maxClerkLeeway)
+	}
+	if err := validateWebhookSecret(c.Clerk.WebhookSigningSecret); err != nil {
+		problem("clerk.webhook_signing_secret: %v", err)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validatePort(port string) error {
+	if port == "" {
+		return fmt.Errorf("is required")
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%q is not a port number between 1 and 65535", port)
+	}
+	return nil
+}
+
+// validateWebhookSecret checks that s is a "whsec_" prefixed base64 secret
+func validateWebhookSecret(s string) error {
+	if s == "" {
+		return fmt.Errorf("is required")
+	}
+	secret, ok := strings.CutPrefix(s, webhookSecretPrefix)
+	if !ok {
+		return fmt.Errorf("must start with %q", webhookSecretPrefix)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil || len(decoded) == 0 {
+		return fmt.Errorf("must be %q followed by base64", webhookSecretPrefix)
+	}
+	return nil
+}
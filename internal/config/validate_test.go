@@ -0,0 +1,108 @@
+package config
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validConfig returns a configuration that passes Validate
+func validConfig(t *testing.T) Config {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Config{
+		Server: Server{Port: "8080"},
+		Database: Database{
+			Host:    "localhost",
+			Port:    "5432",
+			User:    "devarena",
+			DBName:  "devarena",
+			SSLMode: "disable",
+		},
+		Clerk: Clerk{
+			PEMPublicKey:         string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+			WebhookSigningSecret: "whsec_c2VjcmV0",
+			Leeway:               5 * time.Second,
+		},
+	}
+}
+
+func TestValidate(t *testing.T) {
+	base := validConfig(t)
+
+	for _, tt := range []struct {
+		name   string
+		modify func(*Config)
+		want   []string // Problems reported, matched by prefix
+	}{
+		{"valid", func(c *Config) {}, nil},
+		{"JWKS URL instead of a PEM key", func(c *Config) {
+			c.Clerk.PEMPublicKey = ""
+			c.Clerk.JWKSURL = "https://clerk.devarena.dev/.well-known/jwks.json"
+		}, nil},
+		{"issuer and empty sslmode", func(c *Config) {
+			c.Clerk.Issuer = "https://clerk.devarena.dev"
+			c.Database.SSLMode = ""
+		}, nil},
+		{"missing server port", func(c *Config) { c.Server.Port = "" }, []string{"server.port: is required"}},
+		{"port out of range", func(c *Config) { c.Server.Port = "70000" }, []string{"server.port:"}},
+		{"non-numeric database port", func(c *Config) { c.Database.Port = "postgres" }, []string{"database.port:"}},
+		{"missing database settings", func(c *Config) {
+			c.Database.Host, c.Database.User, c.Database.DBName = "", "", ""
+		}, []string{"database.host is required", "database.user is required", "database.dbname is required"}},
+		{"unknown sslmode", func(c *Config) { c.Database.SSLMode = "on" }, []string{"database.sslmode must be one of"}},
+		{"malformed PEM key", func(c *Config) { c.Clerk.PEMPublicKey = "not a key" }, []string{"clerk.pem_public_key:"}},
+		{"no key source", func(c *Config) { c.Clerk.PEMPublicKey = "" }, []string{"clerk.pem_public_key or clerk.jwks_url is required"}},
+		{"relative JWKS URL", func(c *Config) { c.Clerk.JWKSURL = "/.well-known/jwks.json" }, []string{"clerk.jwks_url must be"}},
+		{"issuer without scheme", func(c *Config) { c.Clerk.Issuer = "clerk.devarena.dev" }, []string{"clerk.issuer must be"}},
+		{"negative leeway", func(c *Config) { c.Clerk.Leeway = -time.Second }, []string{"clerk.leeway must be"}},
+		{"leeway too large", func(c *Config) { c.Clerk.Leeway = 2 * time.Minute }, []string{"clerk.leeway must be"}},
+		{"missing webhook secret", func(c *Config) { c.Clerk.WebhookSigningSecret = "" }, []string{"clerk.webhook_signing_secret: is required"}},
+		{"webhook secret without prefix", func(c *Config) { c.Clerk.WebhookSigningSecret = "c2VjcmV0" }, []string{"clerk.webhook_signing_secret: must start with"}},
+		{"webhook secret not base64", func(c *Config) { c.Clerk.WebhookSigningSecret = "whsec_!!" }, []string{"clerk.webhook_signing_secret: must be"}},
+		{"every problem at once", func(c *Config) {
+			c.Server.Port = ""
+			c.Clerk.Leeway = time.Hour
+			c.Clerk.WebhookSigningSecret = ""
+		}, []string{"server.port:", "clerk.leeway must be", "clerk.webhook_signing_secret:"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := base
+			tt.modify(&cfg)
+
+			err := cfg.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Validate error = %v, want a *ValidationError", err)
+			}
+			if len(verr.Problems) != len(tt.want) {
+				t.Fatalf("Problems = %q, want %d matching %q", verr.Problems, len(tt.want), tt.want)
+			}
+			for i, want := range tt.want {
+				if !strings.HasPrefix(verr.Problems[i], want) {
+					t.Errorf("Problems[%d] = %q, want prefix %q", i, verr.Problems[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	// MaxActivityRangeDays is the longest date range an activity request may cover
+	MaxActivityRangeDays = 366
+	// defaultActivityRangeDays is the range shown when no start date is given
+	defaultActivityRangeDays = 365
+
+	activityDateLayout = "2006-01-02"
+)
+
+// ErrInvalidActivityRange is returned when an activity date range is reversed or too long
+var ErrInvalidActivityRange = errors.New("invalid activity range")
+
+// ActivityFilter selects the date range and timeline page of a user's activity.
+// From and To are local dates in the user's time zone; only their year, month
+// and day are used. A zero To means today, a zero From a year before To.
+type ActivityFilter struct {
+	From   time.Time
+	To     time.Time
+	Limit  int
+	Cursor string
+}
+
+// activityCursor is the decoded form of the opaque timeline cursor. It records
+// the last event handed out so the next page can resume after it.
+type activityCursor struct {
+	At  time.Time `json:"at"`
+	Key string    `json:"k"`
+}
+
+func encodeActivityCursor(cur activityCursor) string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeActivityCursor(s string) (activityCursor, error) {
+	var cur activityCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &cur); err != nil || cur.Key == "" {
+		return cur, ErrInvalidCursor
+	}
+	return cur, nil
+}
+
+// localDate returns midnight of t's calendar date in loc
+func localDate(t time.Time, loc *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// GetUserActivity returns the activity calendar and a timeline page of the user
+// with the given username (case-insensitive). Days are counted in the user's
+// own time zone.
+func (db *Database) GetUserActivity(username string, filter ActivityFilter) (*models.UserActivity, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var userID string
+	activity := &models.UserActivity{}
+	err := db.Pool.QueryRow(ctx, `
+		SELECT id, username, COALESCE(timezone, 'UTC') FROM users WHERE lower(username) = lower($1)
+	`, username).Scan(&userID, &activity.Username, &activity.Timezone)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	loc, err := time.LoadLocation(activity.Timezone)
+	if err != nil {
+		loc, activity.Timezone = time.UTC, "UTC"
+	}
+
+	to := localDate(time.Now().In(loc), loc)
+	if !filter.To.IsZero() {
+		to = localDate(filter.To, loc)
+	}
+	from := to.AddDate(0, 0, -(defaultActivityRangeDays - 1))
+	if !filter.From.IsZero() {
+		from = localDate(filter.From, loc)
+	}
+	if to.Before(from) || to.After(from.AddDate(0, 0, MaxActivityRangeDays-1)) {
+		return nil, ErrInvalidActivityRange
+	}
+	activity.From = from.Format(activityDateLayout)
+	activity.To = to.Format(activityDateLayout)
+
+	// The range covers whole local days: [from 00:00, to + 1 day 00:00)
+	start, end := from, to.AddDate(0, 0, 1)
+
+	activity.Days, err = db.getActivityDays(ctx, userID, activity.Timezone, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, day := range activity.Days {
+		activity.TotalSubmissions += day.Submissions
+		activity.TotalReviews += day.Reviews
+	}
+
+	activity.Timeline, activity.NextCursor, err = db.getActivityTimeline(ctx, userID, start, end, filter.Limit, filter.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return activity, nil
+}
+
+// getActivityDays counts a user's submissions and reviews per local day in
+// [start, end), including days without activity
+func (db *Database) getActivityDays(ctx context.Context, userID, timezone string, start, end time.Time) ([]models.ActivityDay, error) {
+	rows, err := db.Pool.Query(ctx, `
+		WITH days AS (
+			SELECT d::date AS day
+			FROM generate_series($3::date, $4::date - 1, interval '1 day') AS d
+		),
+		subs AS (
+			SELECT (created_at AT TIME ZONE $2)::date AS day, COUNT(*) AS n
+			FROM submissions
+			WHERE user_id = $1 AND created_at >= $5 AND created_at < $6
+			GROUP BY 1
+		),
+		revs AS (
+			SELECT (ar.reviewed_at AT TIME ZONE $2)::date AS day, COUNT(*) AS n
+			FROM ai_reviews ar
+			JOIN submissions s ON s.id = ar.submission_id
+			WHERE s.user_id = $1 AND ar.reviewed_at >= $5 AND ar.reviewed_at < $6
+			GROUP BY 1
+		)
+		SELECT days.day, COALESCE(subs.n, 0), COALESCE(revs.n, 0)
+		FROM days
+		LEFT JOIN subs USING (day)
+		LEFT JOIN revs USING (day)
+		ORDER BY days.day
+	`, userID, timezone, start.Format(activityDateLayout), end.Format(activityDateLayout), start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity days: %w", err)
+	}
+	defer rows.Close()
+
+	days := []models.ActivityDay{}
+	for rows.Next() {
+		var day time.Time
+		var d models.ActivityDay
+		if err := rows.Scan(&day, &d.Submissions, &d.Reviews); err != nil {
+			return nil, fmt.Errorf("failed to scan activity day: %w", err)
+		}
+		d.Date = day.Format(activityDateLayout)
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activity days: %w", err)
+	}
+	return days, nil
+}
+
+// getActivityTimeline returns a page of a user's activity events in [start, end),
+// newest first, and the cursor of the next page
+func (db *Database) getActivityTimeline(ctx context.Context, userID string, start, end time.Time, limit int, cursor string) ([]models.ActivityEvent, string, error) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	userArg, startArg, endArg := arg(userID), arg(start), arg(end)
+
+	cursorClause := ""
+	if cursor != "" {
+		cur, err := decodeActivityCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		cursorClause = fmt.Sprintf("WHERE (ev.occurred_at, ev.key) < (%s::timestamptz, %s::text)", arg(cur.At), arg(cur.Key))
+	}
+	limitArg := arg(limit + 1)
+
+	// Each event gets a key that is unique across event types, used as a tiebreaker
+	query := fmt.Sprintf(`
+		SELECT ev.type, ev.occurred_at, ev.key, ev.submission_id, ev.challenge_id,
+			ev.challenge_title, ev.score, ev.starter_pack_id
+		FROM (
+			SELECT 'submitted' AS type, s.created_at AS occurred_at, 'submitted:' || s.id AS key,
+				s.id AS submission_id, s.challenge_id, c.title AS challenge_title,
+				NULL::int AS score, '' AS starter_pack_id
+			FROM submissions s
+			JOIN challenges c ON c.id = s.challenge_id
+			WHERE s.user_id = %[1]s AND s.created_at >= %[2]s AND s.created_at < %[3]s
+
+			UNION ALL
+
+			SELECT 'scored', ar.reviewed_at, 'scored:' || ar.id,
+				s.id, s.challenge_id, c.title,
+				ar.overall_score, ''
+			FROM ai_reviews ar
+			JOIN submissions s ON s.id = ar.submission_id
+			JOIN challenges c ON c.id = s.challenge_id
+			WHERE s.user_id = %[1]s AND ar.reviewed_at >= %[2]s AND ar.reviewed_at < %[3]s
+
+			UNION ALL
+
+			SELECT 'starter_pack_started', sp.created_at, 'starter_pack_started:' || sp.id,
+				'', '', '',
+				NULL, sp.id
+			FROM starter_packs sp
+			WHERE sp.user_id = %[1]s AND sp.created_at >= %[2]s AND sp.created_at < %[3]s
+		) ev
+		%[4]s
+		ORDER BY ev.occurred_at DESC, ev.key DESC
+		LIMIT %[5]s
+	`, userArg, startArg, endArg, cursorClause, limitArg)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query activity timeline: %w", err)
+	}
+	defer rows.Close()
+
+	events := []models.ActivityEvent{}
+	var keys []string
+	for rows.Next() {
+		var ev models.ActivityEvent
+		var key string
+		if err := rows.Scan(
+			&ev.Type, &ev.OccurredAt, &key, &ev.SubmissionID, &ev.ChallengeID,
+			&ev.ChallengeTitle, &ev.Score, &ev.StarterPackID,
+		); err != nil {
+			return nil, "", fmt.Errorf("failed to scan activity event: %w", err)
+		}
+		events = append(events, ev)
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to read activity timeline: %w", err)
+	}
+
+	next := ""
+	if len(events) > limit {
+		events = events[:limit]
+		last := events[limit-1]
+		next = encodeActivityCursor(activityCursor{At: last.OccurredAt, Key: keys[limit-1]})
+	}
+	return events, next, nil
+}
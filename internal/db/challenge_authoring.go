@@ -0,0 +1,324 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Challenge statuses for the admin listing
+const (
+	ChallengeStatusDraft     = "draft"
+	ChallengeStatusPublished = "published"
+)
+
+var (
+	// ErrUnknownTag is returned when a challenge is tagged with a tag that does not exist
+	ErrUnknownTag = errors.New("unknown tag")
+	// ErrChallengeIncomplete is returned when publishing a challenge without requirements
+	ErrChallengeIncomplete = errors.New("challenge has no requirements")
+	// ErrChallengeInUse is returned when deleting a challenge that is published or
+	// that users have already worked on
+	ErrChallengeInUse = errors.New("challenge is published or in use")
+)
+
+// AdminChallengeFilter holds the options for listing challenges to authors
+type AdminChallengeFilter struct {
+	Status string // ChallengeStatusDraft, ChallengeStatusPublished or empty for both
+	Limit  int
+	Offset int
+}
+
+// ListChallengesForAdmin returns challenges including drafts, most recently
+// edited first
+func (db *Database) ListChallengesForAdmin(filter AdminChallengeFilter) ([]models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT
+			c.id, c.title, c.description, c.difficulty, COALESCE(c.type, 'project'),
+			COALESCE(c.max_score, 100), COALESCE(c.repo_template_url, ''),
+			COALESCE(c.requirements, '[]'::jsonb), COALESCE(c.tech_stack, '[]'::jsonb),
+			COALESCE(c.estimated_hours, 0), c.category_weights, COALESCE(c.is_published, FALSE),
+			COALESCE(c.created_at, 'epoch'::timestamptz), COALESCE(c.updated_at, 'epoch'::timestamptz),
+			(SELECT COUNT(*) FROM submissions s WHERE s.challenge_id = c.id),
+			COALESCE((
+				SELECT jsonb_agg(jsonb_build_object(
+					'id', t.id, 'name', t.name, 'slug', t.slug, 'category', COALESCE(t.category, ''),
+					'color', COALESCE(t.color, ''), 'created_at', COALESCE(t.created_at, 'epoch'::timestamptz)
+				) ORDER BY t.name)
+				FROM challenge_tags ct JOIN tags t ON t.id = ct.tag_id
+				WHERE ct.challenge_id = c.id
+			), '[]'::jsonb)
+		FROM challenges c
+		WHERE $1 = '' OR COALESCE(c.is_published, FALSE) = ($1 = 'published')
+		ORDER BY c.updated_at DESC NULLS LAST, c.id
+		LIMIT $2 OFFSET $3
+	`, filter.Status, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.Challenge{}
+	for rows.Next() {
+		var ch models.Challenge
+		if err := rows.Scan(
+			&ch.ID, &ch.Title, &ch.Description, &ch.Difficulty, &ch.Type,
+			&ch.MaxScore, &ch.RepoTemplateURL,
+			&ch.Requirements, &ch.TechStack,
+			&ch.EstimatedHours, &ch.CategoryWeights, &ch.IsPublished,
+			&ch.CreatedAt, &ch.UpdatedAt,
+			&ch.SubmissionCount, &ch.Tags,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		challenges = append(challenges, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read challenges: %w", err)
+	}
+	return challenges, nil
+}
+
+// GetChallengeForAdmin returns a challenge whether or not it is published
+func (db *Database) GetChallengeForAdmin(challengeID string) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return db.getChallenge(ctx, challengeID, false)
+}
+
+// setChallengeTags replaces a challenge's tags. Returns ErrUnknownTag if any of
+// the tags does not exist.
+func setChallengeTags(ctx context.Context, tx pgx.Tx, challengeID string, tagIDs []string) error {
+	unique := []string{}
+	for _, id := range tagIDs {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+
+	var found int
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM tags WHERE id = ANY($1)", unique).Scan(&found); err != nil {
+		return fmt.Errorf("failed to check tags: %w", err)
+	}
+	if found != len(unique) {
+		return ErrUnknownTag
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM challenge_tags WHERE challenge_id = $1", challengeID); err != nil {
+		return fmt.Errorf("failed to clear challenge tags: %w", err)
+	}
+	_, err := tx.Exec(ctx, `
+		INSERT INTO challenge_tags (challenge_id, tag_id)
+		SELECT $1, tag_id FROM unnest($2::text[]) AS tag_id
+	`, challengeID, unique)
+	if err != nil {
+		return fmt.Errorf("failed to tag challenge: %w", err)
+	}
+	return nil
+}
+
+// weightsValue stores empty category weights as NULL so the defaults apply
+func weightsValue(w *models.CategoryWeights) models.CategoryWeights {
+	if w == nil || len(*w) == 0 {
+		return nil
+	}
+	return *w
+}
+
+// CreateChallenge adds a challenge as an unpublished draft. Fields left out
+// get the table defaults.
+func (db *Database) CreateChallenge(req models.ChallengeRequest) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	challengeType := models.ChallengeTypeProject
+	if req.Type != nil {
+		challengeType = *req.Type
+	}
+	maxScore, estimatedHours := 100, 4
+	if req.MaxScore != nil {
+		maxScore = *req.MaxScore
+	}
+	if req.EstimatedHours != nil {
+		estimatedHours = *req.EstimatedHours
+	}
+	requirements, techStack := models.Requirements{}, models.TechStack{}
+	if req.Requirements != nil {
+		requirements = *req.Requirements
+	}
+	if req.TechStack != nil {
+		techStack = *req.TechStack
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	id := uuid.New().String()
+	_, err = tx.Exec(ctx, `
+		INSERT INTO challenges (
+			id, title, description, difficulty, type, max_score, repo_template_url,
+			requirements, tech_stack, estimated_hours, category_weights, is_published,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9, $10, $11, FALSE, NOW(), NOW())
+	`, id, stringValue(req.Title), stringValue(req.Description), string(*req.Difficulty), string(challengeType),
+		maxScore, stringValue(req.RepoTemplateURL), requirements, techStack, estimatedHours,
+		weightsValue(req.CategoryWeights))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert challenge: %w", err)
+	}
+
+	if req.TagIDs != nil {
+		if err := setChallengeTags(ctx, tx, id, *req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit challenge: %w", err)
+	}
+	return db.getChallenge(ctx, id, false)
+}
+
+// UpdateChallenge applies the non-nil fields of req to a challenge and bumps
+// its updated_at. Published challenges stay published.
+func (db *Database) UpdateChallenge(challengeID string, req models.ChallengeRequest) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := []any{challengeID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	sets := []string{"updated_at = NOW()"}
+	set := func(column string, value any) {
+		sets = append(sets, fmt.Sprintf("%s = %s", column, arg(value)))
+	}
+	if req.Title != nil {
+		set("title", *req.Title)
+	}
+	if req.Description != nil {
+		set("description", *req.Description)
+	}
+	if req.Difficulty != nil {
+		set("difficulty", string(*req.Difficulty))
+	}
+	if req.Type != nil {
+		set("type", string(*req.Type))
+	}
+	if req.MaxScore != nil {
+		set("max_score", *req.MaxScore)
+	}
+	if req.RepoTemplateURL != nil {
+		sets = append(sets, fmt.Sprintf("repo_template_url = NULLIF(%s, '')", arg(*req.RepoTemplateURL)))
+	}
+	if req.Requirements != nil {
+		set("requirements", *req.Requirements)
+	}
+	if req.TechStack != nil {
+		set("tech_stack", *req.TechStack)
+	}
+	if req.EstimatedHours != nil {
+		set("estimated_hours", *req.EstimatedHours)
+	}
+	if req.CategoryWeights != nil {
+		set("category_weights", weightsValue(req.CategoryWeights))
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, fmt.Sprintf("UPDATE challenges SET %s WHERE id = $1", strings.Join(sets, ", ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update challenge: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrNotFound
+	}
+
+	if req.TagIDs != nil {
+		if err := setChallengeTags(ctx, tx, challengeID, *req.TagIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit challenge: %w", err)
+	}
+	return db.getChallenge(ctx, challengeID, false)
+}
+
+// SetChallengePublished publishes or unpublishes a challenge. A challenge needs
+// at least one requirement to be published, since reviews are graded against them.
+func (db *Database) SetChallengePublished(challengeID string, published bool) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var updated bool
+	err := db.Pool.QueryRow(ctx, `
+		UPDATE challenges
+		SET is_published = $2, updated_at = NOW()
+		WHERE id = $1 AND (NOT $2 OR jsonb_array_length(COALESCE(requirements, '[]'::jsonb)) > 0)
+		RETURNING TRUE
+	`, challengeID, published).Scan(&updated)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Either the challenge does not exist or it cannot be published yet
+		if _, err := db.getChallenge(ctx, challengeID, false); err != nil {
+			return nil, err
+		}
+		return nil, ErrChallengeIncomplete
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update challenge: %w", err)
+	}
+
+	return db.getChallenge(ctx, challengeID, false)
+}
+
+// DeleteChallenge removes a draft nobody has submitted to or been recommended.
+// Anything else returns ErrChallengeInUse and should be unpublished instead, so
+// user history is never lost.
+func (db *Database) DeleteChallenge(challengeID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.Pool.Exec(ctx, `
+		DELETE FROM challenges c
+		WHERE c.id = $1 AND NOT COALESCE(c.is_published, FALSE)
+			AND NOT EXISTS (SELECT 1 FROM submissions s WHERE s.challenge_id = c.id)
+			AND NOT EXISTS (SELECT 1 FROM starter_pack_challenges spc WHERE spc.challenge_id = c.id)
+	`, challengeID)
+	if err != nil {
+		return fmt.Errorf("failed to delete challenge: %w", err)
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := db.Pool.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM challenges WHERE id = $1)", challengeID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to find challenge: %w", err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrChallengeInUse
+}
@@ -0,0 +1,331 @@
+package db
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/scoring"
+	"github.com/jackc/pgx/v5"
+)
+
+// Sort orders supported by the challenge catalog
+const (
+	ChallengeSortNewest         = "newest"
+	ChallengeSortSuccessRate    = "success_rate"
+	ChallengeSortEstimatedHours = "estimated_hours"
+)
+
+// passedSQL is true when a reviewed submission reaches scoring.PassPercent of its
+// challenge's max score, the same rule as scoring.Passed. Expects submissions
+// aliased as s and challenges as c.
+var passedSQL = fmt.Sprintf(
+	"(s.status = 'reviewed' AND COALESCE(c.max_score, 100) > 0 AND COALESCE(s.score, 0) * 100 >= %d * COALESCE(c.max_score, 100))",
+	scoring.PassPercent,
+)
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ChallengeFilter holds the filtering, sorting and pagination options for the catalog
+type ChallengeFilter struct {
+	Difficulty  models.Difficulty
+	Type        models.ChallengeType
+	TagSlug     string
+	TechStack   string
+	Sort        string
+	Limit       int
+	Cursor      string
+	ClerkUserID string // Optional, used to compute IsSolved for the caller
+}
+
+// ChallengePage is a single page of the challenge catalog
+type ChallengePage struct {
+	Challenges []models.ChallengeResponse `json:"challenges"`
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
+
+// challengeCursor is the decoded form of the opaque cursor handed to clients.
+// It records the sort key of the last row so the next page can resume after it.
+type challengeCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// challengeSortSpec describes how a sort order maps onto the catalog query
+type challengeSortSpec struct {
+	column string // Column in the catalog CTE
+	cast   string // SQL type the cursor value is cast to
+	desc   bool
+}
+
+var challengeSorts = map[string]challengeSortSpec{
+	ChallengeSortNewest:         {column: "created_at", cast: "timestamptz", desc: true},
+	ChallengeSortSuccessRate:    {column: "success_rate", cast: "float8", desc: true},
+	ChallengeSortEstimatedHours: {column: "estimated_hours", cast: "int", desc: false},
+}
+
+// IsValidChallengeSort reports whether sort is a supported catalog sort order
+func IsValidChallengeSort(sort string) bool {
+	_, ok := challengeSorts[sort]
+	return ok
+}
+
+func encodeChallengeCursor(cur challengeCursor) string {
+	raw, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeChallengeCursor(s string) (challengeCursor, error) {
+	var cur challengeCursor
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return cur, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &cur); err != nil || cur.ID == "" {
+		return cur, ErrInvalidCursor
+	}
+	return cur, nil
+}
+
+// ListChallenges returns a page of published challenges matching the filter
+func (db *Database) ListChallenges(filter ChallengeFilter) (*ChallengePage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if filter.Sort == "" {
+		filter.Sort = ChallengeSortNewest
+	}
+	spec, ok := challengeSorts[filter.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort: %s", filter.Sort)
+	}
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where strings.Builder
+	if filter.Difficulty != "" {
+		fmt.Fprintf(&where, " AND c.difficulty = %s", arg(string(filter.Difficulty)))
+	}
+	if filter.Type != "" {
+		fmt.Fprintf(&where, " AND COALESCE(c.type, 'project') = %s", arg(string(filter.Type)))
+	}
+	if filter.TagSlug != "" {
+		fmt.Fprintf(&where, ` AND EXISTS (
+			SELECT 1 FROM challenge_tags ct JOIN tags t ON t.id = ct.tag_id
+			WHERE ct.challenge_id = c.id AND t.slug = %s)`, arg(filter.TagSlug))
+	}
+	if filter.TechStack != "" {
+		fmt.Fprintf(&where, ` AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(COALESCE(c.tech_stack, '[]'::jsonb)) AS ts(name)
+			WHERE lower(ts.name) = lower(%s))`, arg(filter.TechStack))
+	}
+
+	direction, comparator := "ASC", ">"
+	if spec.desc {
+		direction, comparator = "DESC", "<"
+	}
+
+	cursorClause := ""
+	if filter.Cursor != "" {
+		cur, err := decodeChallengeCursor(filter.Cursor)
+		if err != nil || cur.Sort != filter.Sort {
+			return nil, ErrInvalidCursor
+		}
+		cursorClause = fmt.Sprintf("WHERE (cat.%s, cat.id) %s (%s::%s, %s)",
+			spec.column, comparator, arg(cur.Value), spec.cast, arg(cur.ID))
+	}
+
+	userArg := arg(filter.ClerkUserID)
+	limitArg := arg(filter.Limit + 1)
+
+	query := fmt.Sprintf(`
+		WITH catalog AS (
+			SELECT
+				c.id,
+				c.title,
+				c.difficulty,
+				COALESCE(c.type, 'project') AS type,
+				COALESCE(c.max_score, 100) AS max_score,
+				COALESCE(c.tech_stack, '[]'::jsonb) AS tech_stack,
+				COALESCE(c.estimated_hours, 0) AS estimated_hours,
+				COALESCE(c.created_at, 'epoch'::timestamptz) AS created_at,
+				COUNT(s.id) AS submission_count,
+				COALESCE(
+					100.0 * COUNT(DISTINCT s.user_id) FILTER (WHERE %s)
+					/ NULLIF(COUNT(DISTINCT s.user_id), 0),
+				0)::float8 AS success_rate
+			FROM challenges c
+			LEFT JOIN submissions s ON s.challenge_id = c.id
+			WHERE c.is_published = TRUE%s
+			GROUP BY c.id
+		)
+		SELECT
+			cat.id, cat.title, cat.difficulty, cat.type, cat.max_score, cat.tech_stack,
+			cat.estimated_hours, cat.created_at, cat.submission_count, cat.success_rate,
+			COALESCE((
+				SELECT array_agg(t.name ORDER BY t.name)
+				FROM challenge_tags ct JOIN tags t ON t.id = ct.tag_id
+				WHERE ct.challenge_id = cat.id
+			), '{}') AS tags,
+			EXISTS (
+				SELECT 1 FROM submissions s
+				JOIN users u ON u.id = s.user_id
+				JOIN challenges c ON c.id = s.challenge_id
+				WHERE u.clerk_user_id = %s AND s.challenge_id = cat.id AND %s
+			) AS is_solved
+		FROM catalog cat
+		%s
+		ORDER BY cat.%s %s, cat.id %s
+		LIMIT %s
+	`, passedSQL, where.String(), userArg, passedSQL, cursorClause, spec.column, direction, direction, limitArg)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query challenges: %w", err)
+	}
+	defer rows.Close()
+
+	page := &ChallengePage{Challenges: []models.ChallengeResponse{}}
+	var createdAts []time.Time
+	for rows.Next() {
+		var ch models.ChallengeResponse
+		var techStack models.TechStack
+		var createdAt time.Time
+		if err := rows.Scan(
+			&ch.ID, &ch.Title, &ch.Difficulty, &ch.Type, &ch.MaxScore, &techStack,
+			&ch.EstimatedHours, &createdAt, &ch.SubmissionCount, &ch.SuccessRate,
+			&ch.Tags, &ch.IsSolved,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan challenge: %w", err)
+		}
+		ch.TechStack = []string(techStack)
+		if ch.TechStack == nil {
+			ch.TechStack = []string{}
+		}
+		page.Challenges = append(page.Challenges, ch)
+		createdAts = append(createdAts, createdAt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read challenges: %w", err)
+	}
+
+	// We fetched one extra row to find out whether another page exists
+	if len(page.Challenges) > filter.Limit {
+		page.Challenges = page.Challenges[:filter.Limit]
+		last := page.Challenges[len(page.Challenges)-1]
+
+		cur := challengeCursor{Sort: filter.Sort, ID: last.ID}
+		switch filter.Sort {
+		case ChallengeSortNewest:
+			cur.Value = createdAts[filter.Limit-1].UTC().Format(time.RFC3339Nano)
+		case ChallengeSortSuccessRate:
+			cur.Value = strconv.FormatFloat(last.SuccessRate, 'f', -1, 64)
+		case ChallengeSortEstimatedHours:
+			cur.Value = strconv.Itoa(last.EstimatedHours)
+		}
+		page.NextCursor = encodeChallengeCursor(cur)
+	}
+
+	return page, nil
+}
+
+// GetPublishedChallenge returns a published challenge with its tags and submission stats
+func (db *Database) GetPublishedChallenge(challengeID string) (*models.Challenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ch, err := db.getChallenge(ctx, challengeID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scoring weights are an authoring detail
+	ch.CategoryWeights = nil
+	return ch, nil
+}
+
+// getChallenge returns a challenge with its tags and submission stats. Drafts
+// are only returned when publishedOnly is false.
+func (db *Database) getChallenge(ctx context.Context, challengeID string, publishedOnly bool) (*models.Challenge, error) {
+	query := `
+		SELECT
+			c.id, c.title, c.description, c.difficulty, COALESCE(c.type, 'project'),
+			COALESCE(c.max_score, 100), COALESCE(c.repo_template_url, ''),
+			COALESCE(c.requirements, '[]'::jsonb), COALESCE(c.tech_stack, '[]'::jsonb),
+			COALESCE(c.estimated_hours, 0), c.category_weights, COALESCE(c.is_published, FALSE),
+			COALESCE(c.created_at, 'epoch'::timestamptz), COALESCE(c.updated_at, 'epoch'::timestamptz),
+			(SELECT COUNT(*) FROM submissions s WHERE s.challenge_id = c.id),
+			COALESCE((
+				SELECT 100.0 * COUNT(DISTINCT s.user_id) FILTER (WHERE ` + passedSQL + `)
+					/ NULLIF(COUNT(DISTINCT s.user_id), 0)
+				FROM submissions s WHERE s.challenge_id = c.id
+			), 0)::float8
+		FROM challenges c
+		WHERE c.id = $1 AND (c.is_published = TRUE OR NOT $2)
+	`
+
+	var ch models.Challenge
+	err := db.Pool.QueryRow(ctx, query, challengeID, publishedOnly).Scan(
+		&ch.ID, &ch.Title, &ch.Description, &ch.Difficulty, &ch.Type,
+		&ch.MaxScore, &ch.RepoTemplateURL,
+		&ch.Requirements, &ch.TechStack,
+		&ch.EstimatedHours, &ch.CategoryWeights, &ch.IsPublished,
+		&ch.CreatedAt, &ch.UpdatedAt,
+		&ch.SubmissionCount, &ch.SuccessRate,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	ch.Tags, err = db.getChallengeTags(ctx, ch.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ch, nil
+}
+
+// getChallengeTags returns the tags attached to a challenge through challenge_tags
+func (db *Database) getChallengeTags(ctx context.Context, challengeID string) ([]models.Tag, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT t.id, t.name, t.slug, COALESCE(t.category, ''), COALESCE(t.color, ''),
+			COALESCE(t.created_at, 'epoch'::timestamptz)
+		FROM challenge_tags ct
+		JOIN tags t ON t.id = ct.tag_id
+		WHERE ct.challenge_id = $1
+		ORDER BY t.name
+	`, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query challenge tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.Category, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read challenge tags: %w", err)
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,288 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// leaderboardCTE builds the ranking query for a filter. It returns a WITH clause
+// defining "ranked" plus the query arguments; the caller's arguments are appended
+// after them. Each user's score is the sum of their best reviewed submission per
+// challenge within the window. Ties are broken by challenges completed (passed),
+// then by who reached their latest result first, then by user id, so every rank
+// is unique and stable between requests.
+func leaderboardCTE(filter models.LeaderboardFilter) (string, []any) {
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var where strings.Builder
+	switch filter.Period {
+	case models.PeriodWeekly:
+		where.WriteString(" AND s.created_at >= NOW() - INTERVAL '7 days'")
+	case models.PeriodMonthly:
+		where.WriteString(" AND s.created_at >= NOW() - INTERVAL '30 days'")
+	}
+	if filter.Difficulty != "" {
+		fmt.Fprintf(&where, " AND c.difficulty = %s", arg(filter.Difficulty))
+	}
+	if filter.TechStack != "" {
+		fmt.Fprintf(&where, ` AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(COALESCE(c.tech_stack, '[]'::jsonb)) AS ts(name)
+			WHERE lower(ts.name) = lower(%s))`, arg(filter.TechStack))
+	}
+
+	cte := fmt.Sprintf(`
+		WITH filtered AS (
+			SELECT s.id, s.user_id, s.challenge_id, COALESCE(s.score, 0) AS score,
+				%s AS passed, COALESCE(s.created_at, 'epoch'::timestamptz) AS created_at
+			FROM submissions s
+			JOIN challenges c ON c.id = s.challenge_id
+			WHERE s.status = 'reviewed'%s
+		),
+		best AS (
+			SELECT user_id, challenge_id, MAX(score) AS best_score, bool_or(passed) AS passed
+			FROM filtered
+			GROUP BY user_id, challenge_id
+		),
+		totals AS (
+			SELECT user_id, SUM(best_score) AS total_score,
+				COUNT(*) FILTER (WHERE passed) AS challenges_completed
+			FROM best
+			GROUP BY user_id
+		),
+		activity AS (
+			SELECT f.user_id, MAX(f.created_at) AS last_activity_at, AVG(r.overall_score) AS average_review_score
+			FROM filtered f
+			LEFT JOIN ai_reviews r ON r.submission_id = f.id
+			GROUP BY f.user_id
+		),
+		ranked AS (
+			SELECT
+				ROW_NUMBER() OVER (
+					ORDER BY t.total_score DESC, t.challenges_completed DESC, a.last_activity_at ASC, u.id ASC
+				) AS rank,
+				u.id AS user_id,
+				u.clerk_user_id,
+				COALESCE(u.username, '') AS username,
+				COALESCE(u.display_name, '') AS display_name,
+				COALESCE(u.avatar_url, '') AS avatar_url,
+				COALESCE(u.github_username, '') AS github_username,
+				t.total_score,
+				t.challenges_completed,
+				COALESCE(a.average_review_score, 0)::float8 AS average_review_score,
+				%s AS current_streak,
+				a.last_activity_at,
+				NULL::int AS previous_rank
+			FROM totals t
+			JOIN users u ON u.id = t.user_id
+			JOIN activity a ON a.user_id = t.user_id
+		)
+	`, passedSQL, where.String(), currentStreakSQL)
+
+	return cte, args
+}
+
+// snapshotKey returns the leaderboard_snapshots key for a filter and whether that
+// filter is materialized. Snapshots cover each period unfiltered, by a single
+// difficulty, or by a single tech stack.
+func snapshotKey(filter models.LeaderboardFilter) (period, techStack, difficulty string, ok bool) {
+	techStack = strings.ToLower(filter.TechStack)
+	if techStack != "" && filter.Difficulty != "" {
+		return "", "", "", false
+	}
+	return filter.Period, techStack, filter.Difficulty, true
+}
+
+// snapshotCTE defines "ranked" from a materialized snapshot, with the same columns
+// as leaderboardCTE. Profile fields and streaks are joined live from users.
+func snapshotCTE(period, techStack, difficulty string) (string, []any) {
+	return fmt.Sprintf(`
+		WITH ranked AS (
+			SELECT
+				ls.rank,
+				u.id AS user_id,
+				u.clerk_user_id,
+				COALESCE(u.username, '') AS username,
+				COALESCE(u.display_name, '') AS display_name,
+				COALESCE(u.avatar_url, '') AS avatar_url,
+				COALESCE(u.github_username, '') AS github_username,
+				ls.total_score,
+				ls.challenges_completed,
+				ls.average_review_score,
+				%s AS current_streak,
+				COALESCE(ls.last_activity_at, 'epoch'::timestamptz) AS last_activity_at,
+				ls.previous_rank
+			FROM leaderboard_snapshots ls
+			JOIN users u ON u.id = ls.user_id
+			WHERE ls.period = $1 AND ls.tech_stack = $2 AND ls.difficulty = $3
+		)
+	`, currentStreakSQL), []any{period, techStack, difficulty}
+}
+
+// rankedSource returns the "ranked" CTE to read a filter from: the materialized
+// snapshot when one exists, otherwise the live ranking. refreshedAt is nil for live data.
+func (db *Database) rankedSource(ctx context.Context, filter models.LeaderboardFilter) (cte string, args []any, refreshedAt *time.Time, err error) {
+	if period, techStack, difficulty, ok := snapshotKey(filter); ok {
+		var at time.Time
+		err := db.Pool.QueryRow(ctx, `
+			SELECT refreshed_at FROM leaderboard_snapshot_runs
+			WHERE period = $1 AND tech_stack = $2 AND difficulty = $3
+		`, period, techStack, difficulty).Scan(&at)
+		if err == nil {
+			cte, args := snapshotCTE(period, techStack, difficulty)
+			return cte, args, &at, nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return "", nil, nil, fmt.Errorf("failed to look up leaderboard snapshot: %w", err)
+		}
+	}
+
+	cte, args = leaderboardCTE(filter)
+	return cte, args, nil, nil
+}
+
+// leaderboardColumns is the column list read by scanLeaderboardEntries
+const leaderboardColumns = `
+	rank, user_id, username, display_name, avatar_url, github_username, total_score,
+	challenges_completed, average_review_score, current_streak, last_activity_at, previous_rank`
+
+func scanLeaderboardEntries(rows pgx.Rows) ([]models.LeaderboardEntry, int, error) {
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	total := 0
+	for rows.Next() {
+		var e models.LeaderboardEntry
+		var previousRank *int
+		if err := rows.Scan(
+			&e.Rank, &e.UserID, &e.Username, &e.DisplayName, &e.AvatarURL, &e.GitHubUsername,
+			&e.TotalScore, &e.ChallengesCompleted, &e.AverageReviewScore, &e.CurrentStreak,
+			&e.LastActivityAt, &previousRank, &total,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan leaderboard entry: %w", err)
+		}
+		if previousRank != nil {
+			change := *previousRank - e.Rank
+			e.RankChange = &change
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read leaderboard: %w", err)
+	}
+	return entries, total, nil
+}
+
+// GetLeaderboard returns one page of the leaderboard
+func (db *Database) GetLeaderboard(filter models.LeaderboardFilter) (*models.LeaderboardResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cte, args, refreshedAt, err := db.rankedSource(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`%s
+		SELECT %s, COUNT(*) OVER () AS total
+		FROM ranked
+		ORDER BY rank
+		LIMIT $%d OFFSET $%d
+	`, cte, leaderboardColumns, len(args)+1, len(args)+2)
+	args = append(args, filter.Limit, filter.Offset)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard: %w", err)
+	}
+
+	entries, total, err := scanLeaderboardEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// An offset past the end returns no rows, so the window count is not available
+	if len(entries) == 0 && filter.Offset > 0 {
+		countQuery := cte + " SELECT COUNT(*) FROM ranked"
+		if err := db.Pool.QueryRow(ctx, countQuery, args[:len(args)-2]...).Scan(&total); err != nil {
+			return nil, fmt.Errorf("failed to count leaderboard: %w", err)
+		}
+	}
+
+	return &models.LeaderboardResponse{
+		Entries:     entries,
+		Total:       total,
+		Filter:      filter,
+		RefreshedAt: refreshedAt,
+	}, nil
+}
+
+// GetLeaderboardPosition returns a user's leaderboard entry together with up to
+// radius users ranked directly above and below them, regardless of paging
+func (db *Database) GetLeaderboardPosition(clerkUserID string, filter models.LeaderboardFilter, radius int) (*models.LeaderboardPosition, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cte, args, refreshedAt, err := db.rankedSource(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	query := fmt.Sprintf(`%s,
+		me AS (
+			SELECT rank FROM ranked WHERE clerk_user_id = $%d
+		)
+		SELECT %s, (SELECT COUNT(*) FROM ranked) AS total
+		FROM ranked, me
+		WHERE ranked.rank BETWEEN me.rank - $%d AND me.rank + $%d
+		ORDER BY ranked.rank
+	`, cte, len(args)+1, leaderboardColumns, len(args)+2, len(args)+2)
+	args = append(args, clerkUserID, radius)
+
+	rows, err := db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard position: %w", err)
+	}
+
+	entries, total, err := scanLeaderboardEntries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	position := &models.LeaderboardPosition{
+		Neighbors:   []models.LeaderboardEntry{},
+		Total:       total,
+		Filter:      filter,
+		RefreshedAt: refreshedAt,
+	}
+	if len(entries) == 0 {
+		// The user is not ranked in this window; still report how many users are
+		countQuery := cte + " SELECT COUNT(*) FROM ranked"
+		if err := db.Pool.QueryRow(ctx, countQuery, args[:len(args)-2]...).Scan(&position.Total); err != nil {
+			return nil, fmt.Errorf("failed to count leaderboard: %w", err)
+		}
+		return position, nil
+	}
+
+	userID, err := db.GetUserIDByClerkID(clerkUserID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].UserID == userID {
+			entry := entries[i]
+			position.Entry = &entry
+			continue
+		}
+		position.Neighbors = append(position.Neighbors, entries[i])
+	}
+
+	return position, nil
+}
@@ -0,0 +1,212 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// rankHistoryRetentionDays is how long daily ranks are kept for computing deltas
+	rankHistoryRetentionDays = 90
+	// leaderboardRefreshLockKey is the advisory lock that keeps instances from
+	// refreshing snapshots at the same time
+	leaderboardRefreshLockKey int64 = 0x6c6264726566 // "lbdref"
+)
+
+// snapshotFilters lists every leaderboard filter that is materialized: each period
+// unfiltered, by difficulty, and by each tech stack used by a published challenge
+func (db *Database) snapshotFilters(ctx context.Context, conn *pgxpool.Conn) ([]models.LeaderboardFilter, error) {
+	rows, err := conn.Query(ctx, `
+		SELECT DISTINCT lower(ts.name)
+		FROM challenges c, jsonb_array_elements_text(COALESCE(c.tech_stack, '[]'::jsonb)) AS ts(name)
+		WHERE c.is_published = true AND ts.name <> ''
+		ORDER BY 1
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tech stacks: %w", err)
+	}
+	defer rows.Close()
+
+	var techStacks []string
+	for rows.Next() {
+		var tech string
+		if err := rows.Scan(&tech); err != nil {
+			return nil, fmt.Errorf("failed to scan tech stack: %w", err)
+		}
+		techStacks = append(techStacks, tech)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tech stacks: %w", err)
+	}
+
+	difficulties := []models.Difficulty{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
+
+	var filters []models.LeaderboardFilter
+	for _, period := range []string{models.PeriodAllTime, models.PeriodWeekly, models.PeriodMonthly} {
+		filters = append(filters, models.LeaderboardFilter{Period: period})
+		for _, d := range difficulties {
+			filters = append(filters, models.LeaderboardFilter{Period: period, Difficulty: string(d)})
+		}
+		for _, tech := range techStacks {
+			filters = append(filters, models.LeaderboardFilter{Period: period, TechStack: tech})
+		}
+	}
+	return filters, nil
+}
+
+// refreshSnapshot recomputes the snapshot for one filter in a single transaction,
+// so readers see either the previous snapshot or the new one in full
+func (db *Database) refreshSnapshot(ctx context.Context, conn *pgxpool.Conn, filter models.LeaderboardFilter) error {
+	period, techStack, difficulty, _ := snapshotKey(filter)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		DELETE FROM leaderboard_snapshots
+		WHERE period = $1 AND tech_stack = $2 AND difficulty = $3
+	`, period, techStack, difficulty)
+	if err != nil {
+		return fmt.Errorf("failed to clear leaderboard snapshot: %w", err)
+	}
+
+	cte, args := leaderboardCTE(filter)
+	n := len(args)
+	query := fmt.Sprintf(`%s
+		INSERT INTO leaderboard_snapshots (
+			period, tech_stack, difficulty, user_id, rank, previous_rank, total_score,
+			challenges_completed, average_review_score, last_activity_at
+		)
+		SELECT $%d, $%d, $%d, r.user_id, r.rank, h.rank, r.total_score,
+			r.challenges_completed, r.average_review_score, r.last_activity_at
+		FROM ranked r
+		LEFT JOIN leaderboard_rank_history h
+			ON h.period = $%d AND h.tech_stack = $%d AND h.difficulty = $%d
+			AND h.user_id = r.user_id
+			AND h.snapshot_date = (NOW() AT TIME ZONE 'UTC')::date - 1
+	`, cte, n+1, n+2, n+3, n+1, n+2, n+3)
+	args = append(args, period, techStack, difficulty)
+	if _, err := tx.Exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to write leaderboard snapshot: %w", err)
+	}
+
+	// The last refresh of a day becomes that day's rank for tomorrow's deltas
+	_, err = tx.Exec(ctx, `
+		INSERT INTO leaderboard_rank_history (period, tech_stack, difficulty, user_id, snapshot_date, rank)
+		SELECT period, tech_stack, difficulty, user_id, (NOW() AT TIME ZONE 'UTC')::date, rank
+		FROM leaderboard_snapshots
+		WHERE period = $1 AND tech_stack = $2 AND difficulty = $3
+		ON CONFLICT (period, tech_stack, difficulty, user_id, snapshot_date)
+		DO UPDATE SET rank = EXCLUDED.rank
+	`, period, techStack, difficulty)
+	if err != nil {
+		return fmt.Errorf("failed to record rank history: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO leaderboard_snapshot_runs (period, tech_stack, difficulty, refreshed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (period, tech_stack, difficulty) DO UPDATE SET refreshed_at = EXCLUDED.refreshed_at
+	`, period, techStack, difficulty)
+	if err != nil {
+		return fmt.Errorf("failed to record leaderboard snapshot run: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit leaderboard snapshot: %w", err)
+	}
+	return nil
+}
+
+// RefreshLeaderboardSnapshots rematerializes every leaderboard filter, drops
+// snapshots for filters that no longer exist (e.g. a retired tech stack), syncs
+// users.rank with the all-time leaderboard and prunes old rank history. The run is
+// skipped when another instance is refreshing at the same time.
+func (db *Database) RefreshLeaderboardSnapshots(ctx context.Context) error {
+	// The refresh lock is held by this session for the whole run, so every
+	// statement below goes through the same connection
+	conn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", leaderboardRefreshLockKey).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to take leaderboard refresh lock: %w", err)
+	}
+	if !locked {
+		log.Printf("Leaderboard snapshots are being refreshed by another instance, skipping this run")
+		return nil
+	}
+	defer func() {
+		// ctx may already be cancelled; the lock must still be released before the
+		// connection goes back to the pool
+		unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlockCtx, "SELECT pg_advisory_unlock($1)", leaderboardRefreshLockKey); err != nil {
+			log.Printf("Failed to release leaderboard refresh lock, closing its connection: %v", err)
+			conn.Conn().Close(unlockCtx)
+		}
+	}()
+
+	var cycleStart time.Time
+	if err := conn.QueryRow(ctx, "SELECT NOW()").Scan(&cycleStart); err != nil {
+		return fmt.Errorf("failed to read database time: %w", err)
+	}
+
+	filters, err := db.snapshotFilters(ctx, conn)
+	if err != nil {
+		return err
+	}
+	for _, filter := range filters {
+		if err := db.refreshSnapshot(ctx, conn, filter); err != nil {
+			return fmt.Errorf("period=%s tech_stack=%q difficulty=%q: %w",
+				filter.Period, filter.TechStack, filter.Difficulty, err)
+		}
+	}
+
+	_, err = conn.Exec(ctx, `
+		WITH stale AS (
+			DELETE FROM leaderboard_snapshot_runs
+			WHERE refreshed_at < $1
+			RETURNING period, tech_stack, difficulty
+		)
+		DELETE FROM leaderboard_snapshots ls
+		USING stale
+		WHERE ls.period = stale.period AND ls.tech_stack = stale.tech_stack AND ls.difficulty = stale.difficulty
+	`, cycleStart)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale leaderboard snapshots: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+		UPDATE users u
+		SET rank = COALESCE(ls.rank, 0)
+		FROM users u2
+		LEFT JOIN leaderboard_snapshots ls
+			ON ls.user_id = u2.id AND ls.period = $1 AND ls.tech_stack = '' AND ls.difficulty = ''
+		WHERE u.id = u2.id AND COALESCE(u.rank, 0) <> COALESCE(ls.rank, 0)
+	`, models.PeriodAllTime)
+	if err != nil {
+		return fmt.Errorf("failed to update user ranks: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+		DELETE FROM leaderboard_rank_history
+		WHERE snapshot_date < (NOW() AT TIME ZONE 'UTC')::date - $1::int
+	`, rankHistoryRetentionDays)
+	if err != nil {
+		return fmt.Errorf("failed to prune rank history: %w", err)
+	}
+
+	return nil
+}
@@ -22,75 +22,61 @@ func (db *Database) SaveOnboardingData(clerkUserID string, onboardingData struct
 	Paths        []string `json:"paths"`
 	Technologies []string `json:"technologies"`
 }) error {
-	fmt.Printf("[DEBUG] SaveOnboardingData called with clerkUserID: %s\n", clerkUserID)
-	fmt.Printf("[DEBUG] Onboarding data: Experience=%s, Paths=%v, Technologies=%v\n",
-		onboardingData.Experience, onboardingData.Paths, onboardingData.Technologies)
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	// First, get the internal user ID from clerk_user_id
 	var internalUserID string
-	fmt.Printf("[DEBUG] Looking up user with clerk_user_id: %s\n", clerkUserID)
 	err := db.Pool.QueryRow(ctx,
 		"SELECT id FROM users WHERE clerk_user_id = $1",
 		clerkUserID,
 	).Scan(&internalUserID)
 	if err != nil {
-		fmt.Printf("[DEBUG] ERROR: Failed to find user with clerk_user_id %s: %v\n", clerkUserID, err)
 		return fmt.Errorf("failed to find user: %w", err)
 	}
-	fmt.Printf("[DEBUG] Found internal user ID: %s\n", internalUserID)
 
 	// Convert slices to JSON for JSONB columns
 	pathsJSON, err := json.Marshal(onboardingData.Paths)
 	if err != nil {
-		fmt.Printf("[DEBUG] ERROR: Failed to marshal paths: %v\n", err)
-		return err
+		return fmt.Errorf("failed to marshal paths: %w", err)
 	}
 
 	techJSON, err := json.Marshal(onboardingData.Technologies)
 	if err != nil {
-		fmt.Printf("[DEBUG] ERROR: Failed to marshal technologies: %v\n", err)
-		return err
+		return fmt.Errorf("failed to marshal technologies: %w", err)
 	}
-	fmt.Printf("[DEBUG] Marshaled JSON - paths: %s, tech: %s\n", string(pathsJSON), string(techJSON))
 
-	// Generate a new UUID for the starter pack
-	starterPackID := uuid.New().String()
-	fmt.Printf("[DEBUG] Generated starter pack ID: %s\n", starterPackID)
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
 
-	// Insert or update the starter pack
-	query := `
-		INSERT INTO starter_packs (id, user_id, experience, paths, technologies, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
-		ON CONFLICT (user_id) DO UPDATE SET
-			experience = EXCLUDED.experience,
-			paths = EXCLUDED.paths,
-			technologies = EXCLUDED.technologies,
-			updated_at = NOW()
-	`
+	// Archive the current pack so its progress is kept as history
+	if err := archiveActiveStarterPack(ctx, tx, internalUserID); err != nil {
+		return err
+	}
 
-	fmt.Printf("[DEBUG] Executing INSERT into starter_packs...\n")
-	_, err = db.Pool.Exec(ctx, query, starterPackID, internalUserID, onboardingData.Experience, pathsJSON, techJSON)
+	// Start a new active starter pack from the answers
+	_, err = tx.Exec(ctx, `
+		INSERT INTO starter_packs (id, user_id, experience, paths, technologies, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, TRUE, NOW(), NOW())
+	`, uuid.New().String(), internalUserID, onboardingData.Experience, pathsJSON, techJSON)
 	if err != nil {
-		fmt.Printf("[DEBUG] ERROR: Failed to insert starter pack: %v\n", err)
-		return err
+		return fmt.Errorf("failed to insert starter pack: %w", err)
 	}
-	fmt.Printf("[DEBUG] Successfully inserted/updated starter pack\n")
 
 	// Mark user's onboarding as completed
-	updateUserQuery := `
+	_, err = tx.Exec(ctx, `
 		UPDATE users SET onboarding_completed = TRUE, updated_at = NOW()
 		WHERE id = $1
-	`
-	fmt.Printf("[DEBUG] Updating onboarding_completed for user ID: %s\n", internalUserID)
-	_, err = db.Pool.Exec(ctx, updateUserQuery, internalUserID)
+	`, internalUserID)
 	if err != nil {
-		fmt.Printf("[DEBUG] ERROR: Failed to update onboarding_completed: %v\n", err)
-	} else {
-		fmt.Printf("[DEBUG] Successfully marked onboarding as completed\n")
+		return fmt.Errorf("failed to mark onboarding completed: %w", err)
 	}
 
-	return err
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit starter pack: %w", err)
+	}
+	return nil
 }
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"time"
@@ -10,6 +11,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// ErrNotFound is returned when a requested row does not exist
+var ErrNotFound = errors.New("not found")
+
 type Database struct {
 	Pool *pgxpool.Pool
 }
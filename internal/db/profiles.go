@@ -0,0 +1,145 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// recentSubmissionsLimit is how many reviewed submissions a public profile shows
+const recentSubmissionsLimit = 10
+
+// GetPublicProfile returns the public profile of the user with the given
+// username (case-insensitive), with their stats, per-difficulty progress and
+// latest reviewed submissions
+func (db *Database) GetPublicProfile(username string) (*models.PublicProfile, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var p models.PublicProfile
+	err := db.Pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT u.id, u.username, COALESCE(u.display_name, ''), COALESCE(u.avatar_url, ''),
+			COALESCE(u.bio, ''), COALESCE(u.github_username, ''),
+			COALESCE(u.total_score, 0), COALESCE(u.rank, 0), COALESCE(u.longest_streak, 0),
+			COALESCE(u.created_at, 'epoch'::timestamptz),
+			COALESCE(u.challenges_completed, 0), %s,
+			COALESCE(r.average_score, 0)::float8, COALESCE(r.total_reviews, 0),
+			CASE WHEN COALESCE(u.total_score, 0) > 0 THEN
+				100.0 * (SELECT COUNT(*) FROM users o WHERE o.total_score >= u.total_score)
+					/ (SELECT COUNT(*) FROM users o WHERE o.total_score > 0)
+			ELSE 0 END::float8
+		FROM users u
+		LEFT JOIN LATERAL (
+			SELECT AVG(ar.overall_score) AS average_score, COUNT(*) AS total_reviews
+			FROM ai_reviews ar
+			JOIN submissions s ON s.id = ar.submission_id
+			WHERE s.user_id = u.id
+		) r ON TRUE
+		WHERE lower(u.username) = lower($1)
+	`, currentStreakSQL), username).Scan(
+		&p.Stats.UserID, &p.Username, &p.DisplayName, &p.AvatarURL,
+		&p.Bio, &p.GitHubUsername,
+		&p.TotalScore, &p.Rank, &p.LongestStreak,
+		&p.JoinedAt,
+		&p.Stats.ChallengesCompleted, &p.Stats.CurrentStreak,
+		&p.Stats.AverageScore, &p.Stats.TotalReviews,
+		&p.Stats.TopPercentile,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+
+	p.DifficultyBreakdown, err = db.getDifficultyBreakdown(ctx, p.Stats.UserID)
+	if err != nil {
+		return nil, err
+	}
+	p.RecentSubmissions, err = db.getRecentReviewedSubmissions(ctx, p.Stats.UserID, recentSubmissionsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// getDifficultyBreakdown counts a user's attempted and completed (passed)
+// challenges per difficulty
+func (db *Database) getDifficultyBreakdown(ctx context.Context, userID string) ([]models.DifficultyStats, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT c.difficulty,
+			COUNT(DISTINCT s.challenge_id),
+			COUNT(DISTINCT s.challenge_id) FILTER (WHERE `+passedSQL+`),
+			COUNT(DISTINCT c.id) FILTER (WHERE c.is_published = TRUE)
+		FROM challenges c
+		LEFT JOIN submissions s ON s.challenge_id = c.id AND s.user_id = $1
+		GROUP BY c.difficulty
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query difficulty breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	byDifficulty := map[models.Difficulty]models.DifficultyStats{}
+	for rows.Next() {
+		var d models.DifficultyStats
+		if err := rows.Scan(&d.Difficulty, &d.Attempted, &d.Completed, &d.Available); err != nil {
+			return nil, fmt.Errorf("failed to scan difficulty breakdown: %w", err)
+		}
+		byDifficulty[d.Difficulty] = d
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read difficulty breakdown: %w", err)
+	}
+
+	// Always report every difficulty, easiest first, so clients can render a fixed layout
+	breakdown := []models.DifficultyStats{}
+	for _, difficulty := range []models.Difficulty{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard} {
+		d := byDifficulty[difficulty]
+		d.Difficulty = difficulty
+		breakdown = append(breakdown, d)
+	}
+	return breakdown, nil
+}
+
+// getRecentReviewedSubmissions returns a user's latest reviewed submissions
+func (db *Database) getRecentReviewedSubmissions(ctx context.Context, userID string, limit int) ([]models.PublicSubmission, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT s.id, s.challenge_id, c.title, c.difficulty, s.repo_url,
+			COALESCE(s.score, 0), COALESCE(c.max_score, 100),
+			COALESCE(r.reviewed_at, s.updated_at, s.created_at, 'epoch'::timestamptz) AS reviewed_at
+		FROM submissions s
+		JOIN challenges c ON c.id = s.challenge_id
+		LEFT JOIN LATERAL (
+			SELECT MAX(reviewed_at) AS reviewed_at FROM ai_reviews WHERE submission_id = s.id
+		) r ON TRUE
+		WHERE s.user_id = $1 AND s.status = 'reviewed'
+		ORDER BY reviewed_at DESC, s.id
+		LIMIT $2
+	`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []models.PublicSubmission{}
+	for rows.Next() {
+		var sub models.PublicSubmission
+		if err := rows.Scan(
+			&sub.ID, &sub.ChallengeID, &sub.ChallengeTitle, &sub.Difficulty, &sub.RepoURL,
+			&sub.Score, &sub.MaxScore, &sub.ReviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		submissions = append(submissions, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recent submissions: %w", err)
+	}
+	return submissions, nil
+}
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// ClaimPendingSubmission atomically moves the oldest due pending submission to
+// "reviewing" and returns it. It returns nil, nil when the queue is empty.
+// SKIP LOCKED lets several workers (and several server instances) poll concurrently
+// without handing out the same submission twice.
+func (db *Database) ClaimPendingSubmission(ctx context.Context) (*models.Submission, error) {
+	query := `
+		UPDATE submissions
+		SET status = 'reviewing',
+			attempts = COALESCE(attempts, 0) + 1,
+			claimed_at = NOW(),
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM submissions
+			WHERE status = 'pending'
+				AND (next_attempt_at IS NULL OR next_attempt_at <= NOW())
+			ORDER BY next_attempt_at NULLS FIRST, created_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING id, user_id, challenge_id, repo_url, COALESCE(branch, 'main'),
+			COALESCE(commit_hash, ''), status, COALESCE(score, 0), attempts,
+			COALESCE(created_at, 'epoch'::timestamptz), updated_at
+	`
+
+	var sub models.Submission
+	err := db.Pool.QueryRow(ctx, query).Scan(
+		&sub.ID, &sub.UserID, &sub.ChallengeID, &sub.RepoURL, &sub.Branch,
+		&sub.CommitHash, &sub.Status, &sub.Score, &sub.Attempts,
+		&sub.CreatedAt, &sub.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to claim submission: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// MarkSubmissionReviewed completes a claimed submission
+func (db *Database) MarkSubmissionReviewed(ctx context.Context, submissionID string) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE submissions
+		SET status = 'reviewed', failure_reason = NULL, claimed_at = NULL, next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'reviewing'
+	`, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to mark submission reviewed: %w", err)
+	}
+	return nil
+}
+
+// RetrySubmission puts a claimed submission back in the queue after delay,
+// keeping the error that caused the retry
+func (db *Database) RetrySubmission(ctx context.Context, submissionID, reason string, delay time.Duration) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE submissions
+		SET status = 'pending',
+			failure_reason = $2,
+			next_attempt_at = NOW() + make_interval(secs => $3),
+			claimed_at = NULL,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'reviewing'
+	`, submissionID, reason, delay.Seconds())
+	if err != nil {
+		return fmt.Errorf("failed to reschedule submission: %w", err)
+	}
+	return nil
+}
+
+// FailSubmission marks a claimed submission as permanently failed
+func (db *Database) FailSubmission(ctx context.Context, submissionID, reason string) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE submissions
+		SET status = 'failed', failure_reason = $2, claimed_at = NULL, next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = 'reviewing'
+	`, submissionID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to mark submission failed: %w", err)
+	}
+	return nil
+}
+
+// ReleaseSubmission returns a claimed submission to the queue without counting
+// the attempt, used when a review is interrupted by shutdown
+func (db *Database) ReleaseSubmission(ctx context.Context, submissionID string) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE submissions
+		SET status = 'pending',
+			attempts = GREATEST(COALESCE(attempts, 1) - 1, 0),
+			claimed_at = NULL,
+			updated_at = NOW()
+		WHERE id = $1 AND status = 'reviewing'
+	`, submissionID)
+	if err != nil {
+		return fmt.Errorf("failed to release submission: %w", err)
+	}
+	return nil
+}
+
+// RequeueStaleSubmissions returns submissions stuck in "reviewing" for longer than
+// staleAfter to the queue, recovering work from workers that died mid-review.
+// Submissions that already used maxAttempts are failed instead, so one that
+// crashes its worker every time is not retried forever.
+func (db *Database) RequeueStaleSubmissions(ctx context.Context, staleAfter time.Duration, maxAttempts int) (requeued, failed int64, err error) {
+	err = db.Pool.QueryRow(ctx, `
+		WITH stale AS (
+			UPDATE submissions
+			SET status = CASE WHEN COALESCE(attempts, 0) >= $2 THEN 'failed' ELSE 'pending' END,
+				failure_reason = CASE
+					WHEN COALESCE(attempts, 0) >= $2 THEN 'Review did not finish after ' || $2 || ' attempts'
+					ELSE failure_reason
+				END,
+				next_attempt_at = CASE WHEN COALESCE(attempts, 0) >= $2 THEN NULL ELSE next_attempt_at END,
+				claimed_at = NULL,
+				updated_at = NOW()
+			WHERE status = 'reviewing'
+				AND (claimed_at IS NULL OR claimed_at < NOW() - make_interval(secs => $1))
+			RETURNING status
+		)
+		SELECT COUNT(*) FILTER (WHERE status = 'pending'), COUNT(*) FILTER (WHERE status = 'failed')
+		FROM stale
+	`, staleAfter.Seconds(), maxAttempts).Scan(&requeued, &failed)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to requeue stale submissions: %w", err)
+	}
+	return requeued, failed, nil
+}
+
+// PinSubmissionCommit records the commit that was actually reviewed
+func (db *Database) PinSubmissionCommit(ctx context.Context, submissionID, commitHash string) error {
+	_, err := db.Pool.Exec(ctx,
+		"UPDATE submissions SET commit_hash = $2, updated_at = NOW() WHERE id = $1",
+		submissionID, commitHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to pin submission commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetChallengeForReview returns the parts of a challenge needed to review and
+// score a submission: requirements, max score and category weights
+func (db *Database) GetChallengeForReview(ctx context.Context, challengeID string) (*models.Challenge, error) {
+	var ch models.Challenge
+	err := db.Pool.QueryRow(ctx, `
+		SELECT id, COALESCE(requirements, '[]'::jsonb), COALESCE(max_score, 100), category_weights
+		FROM challenges
+		WHERE id = $1
+	`, challengeID).Scan(&ch.ID, &ch.Requirements, &ch.MaxScore, &ch.CategoryWeights)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+	return &ch, nil
+}
+
+// SaveReviewResult stores a review, writes the submission's score, marks it
+// reviewed, recomputes the user's totals and, when the submission passed,
+// advances their active starter pack, all in one transaction. Only the best
+// reviewed submission per challenge counts toward total_score, so resubmitting a
+// worse solution never lowers it; challenges_completed counts the challenges
+// with a passing submission.
+func (db *Database) SaveReviewResult(ctx context.Context, review *models.AIReview, score int, passed bool) error {
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the owner's row so concurrent reviews for the same user serialize
+	var userID string
+	err = tx.QueryRow(ctx, `
+		SELECT u.id FROM users u
+		JOIN submissions s ON s.user_id = u.id
+		WHERE s.id = $1
+		FOR UPDATE OF u
+	`, review.SubmissionID).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("failed to lock submission owner: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO ai_reviews (id, submission_id, overall_score, categories, feedback, suggestions, reviewed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, review.ID, review.SubmissionID, review.OverallScore, review.Categories, review.Feedback, review.Suggestions, review.ReviewedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert review: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE submissions
+		SET score = $2, status = 'reviewed', failure_reason = NULL, claimed_at = NULL,
+			next_attempt_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`, review.SubmissionID, score)
+	if err != nil {
+		return fmt.Errorf("failed to update submission score: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE users SET
+			total_score = best.total_score,
+			challenges_completed = best.challenges_completed,
+			updated_at = NOW()
+		FROM (
+			SELECT COALESCE(SUM(best_score), 0) AS total_score,
+				COUNT(*) FILTER (WHERE passed) AS challenges_completed
+			FROM (
+				SELECT MAX(s.score) AS best_score, bool_or(`+passedSQL+`) AS passed
+				FROM submissions s
+				JOIN challenges c ON c.id = s.challenge_id
+				WHERE s.user_id = $1 AND s.status = 'reviewed'
+				GROUP BY s.challenge_id
+			) per_challenge
+		) best
+		WHERE users.id = $1
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update user totals: %w", err)
+	}
+
+	if passed {
+		if err := completeStarterPackChallenge(ctx, tx, userID, review.SubmissionID, score); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit review: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetUserRole returns the role of the user with the given Clerk ID
+func (db *Database) GetUserRole(ctx context.Context, clerkUserID string) (models.Role, error) {
+	var role models.Role
+	err := db.Pool.QueryRow(ctx, "SELECT role FROM users WHERE clerk_user_id = $1", clerkUserID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to get user role: %w", err)
+	}
+	return role, nil
+}
+
+// SetUserRole stores the role of the user with the given Clerk ID
+func (db *Database) SetUserRole(ctx context.Context, clerkUserID string, role models.Role) error {
+	result, err := db.Pool.Exec(ctx,
+		"UPDATE users SET role = $2, updated_at = NOW() WHERE clerk_user_id = $1 AND role <> $2",
+		clerkUserID, string(role),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set user role: %w", err)
+	}
+	if result.RowsAffected() > 0 {
+		return nil
+	}
+
+	// Nothing changed: either the role was already set or the user does not exist
+	_, err = db.GetUserRole(ctx, clerkUserID)
+	return err
+}
+
+// UpdateUserRole changes the role of the user with the given username (case-insensitive)
+func (db *Database) UpdateUserRole(username string, role models.Role) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanUser(db.Pool.QueryRow(ctx,
+		"UPDATE users SET role = $2, updated_at = NOW() WHERE lower(username) = lower($1) RETURNING "+userColumns,
+		username, string(role),
+	))
+}
@@ -0,0 +1,431 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/starterpack"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// listPackCandidates returns every published challenge with its tags, the pool
+// starter packs are generated from
+func listPackCandidates(ctx context.Context, tx pgx.Tx) ([]starterpack.Candidate, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c.id, c.difficulty, COALESCE(c.tech_stack, '[]'::jsonb), COALESCE(c.estimated_hours, 0),
+			COALESCE(
+				jsonb_agg(jsonb_build_object('name', t.name, 'slug', t.slug, 'category', COALESCE(t.category, '')))
+					FILTER (WHERE t.id IS NOT NULL),
+				'[]'::jsonb
+			)
+		FROM challenges c
+		LEFT JOIN challenge_tags ct ON ct.challenge_id = c.id
+		LEFT JOIN tags t ON t.id = ct.tag_id
+		WHERE c.is_published = TRUE
+		GROUP BY c.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pack candidates: %w", err)
+	}
+	defer rows.Close()
+
+	var candidates []starterpack.Candidate
+	for rows.Next() {
+		var c starterpack.Candidate
+		var techStack models.StringArray
+		var tagsJSON []byte
+		if err := rows.Scan(&c.ID, &c.Difficulty, &techStack, &c.EstimatedHours, &tagsJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan pack candidate: %w", err)
+		}
+		if err := json.Unmarshal(tagsJSON, &c.Tags); err != nil {
+			return nil, fmt.Errorf("failed to decode tags of challenge %s: %w", c.ID, err)
+		}
+		c.TechStack = techStack
+		candidates = append(candidates, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read pack candidates: %w", err)
+	}
+	return candidates, nil
+}
+
+// archiveActiveStarterPack deactivates the user's active pack, keeping its
+// challenges and progress as history
+func archiveActiveStarterPack(ctx context.Context, tx pgx.Tx, userID string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE starter_packs
+		SET is_active = FALSE, archived_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND is_active
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to archive starter pack: %w", err)
+	}
+	return nil
+}
+
+// fillStarterPack replaces a pack's challenges with ones recommended from its
+// onboarding answers and clears its progress
+func fillStarterPack(ctx context.Context, tx pgx.Tx, pack *models.StarterPack) error {
+	candidates, err := listPackCandidates(ctx, tx)
+	if err != nil {
+		return err
+	}
+	challengeIDs := starterpack.Recommend(starterpack.Preferences{
+		Experience:   pack.Experience,
+		Paths:        pack.Paths,
+		Technologies: pack.Technologies,
+	}, candidates)
+
+	if _, err := tx.Exec(ctx, "DELETE FROM starter_pack_challenges WHERE starter_pack_id = $1", pack.ID); err != nil {
+		return fmt.Errorf("failed to clear starter pack challenges: %w", err)
+	}
+
+	batch := &pgx.Batch{}
+	for i, challengeID := range challengeIDs {
+		batch.Queue(`
+			INSERT INTO starter_pack_challenges (id, starter_pack_id, challenge_id, order_index)
+			VALUES ($1, $2, $3, $4)
+		`, uuid.New().String(), pack.ID, challengeID, i)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("failed to insert starter pack challenges: %w", err)
+	}
+
+	pack.Challenges = challengeIDs
+	pack.TotalChallenges = len(challengeIDs)
+	pack.CurrentProgress = 0
+	err = tx.QueryRow(ctx, `
+		UPDATE starter_packs
+		SET challenge_ids = $2, total_challenges = $3, current_progress = 0, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, pack.ID, pack.Challenges, pack.TotalChallenges).Scan(&pack.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update starter pack: %w", err)
+	}
+	return nil
+}
+
+// lockStarterPack loads and locks the user's active pack, or their most recent
+// pack when activeOnly is false
+func lockStarterPack(ctx context.Context, tx pgx.Tx, clerkUserID string, activeOnly bool) (*models.StarterPack, error) {
+	var pack models.StarterPack
+	err := tx.QueryRow(ctx, `
+		SELECT sp.id, sp.user_id, COALESCE(sp.experience, ''),
+			COALESCE(sp.paths, '[]'::jsonb), COALESCE(sp.technologies, '[]'::jsonb),
+			sp.is_active, COALESCE(sp.created_at, 'epoch'::timestamptz)
+		FROM starter_packs sp
+		JOIN users u ON u.id = sp.user_id
+		WHERE u.clerk_user_id = $1 AND (sp.is_active OR NOT $2)
+		ORDER BY sp.is_active DESC, sp.created_at DESC
+		LIMIT 1
+		FOR UPDATE OF sp
+	`, clerkUserID, activeOnly).Scan(
+		&pack.ID, &pack.UserID, &pack.Experience, &pack.Paths, &pack.Technologies,
+		&pack.IsActive, &pack.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to find starter pack: %w", err)
+	}
+	return &pack, nil
+}
+
+// GenerateStarterPack fills the user's active starter pack with challenges
+// recommended from their onboarding answers, replacing any previous selection
+// and progress
+func (db *Database) GenerateStarterPack(clerkUserID string) (*models.StarterPack, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	pack, err := lockStarterPack(ctx, tx, clerkUserID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := fillStarterPack(ctx, tx, pack); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit starter pack: %w", err)
+	}
+	return pack, nil
+}
+
+// RegenerateStarterPack archives the user's active pack and starts a new one from
+// the same onboarding answers, picking up newly published challenges. It also
+// works when the user has only archived packs, reusing the latest answers.
+func (db *Database) RegenerateStarterPack(clerkUserID string) (*models.StarterPack, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	previous, err := lockStarterPack(ctx, tx, clerkUserID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := archiveActiveStarterPack(ctx, tx, previous.UserID); err != nil {
+		return nil, err
+	}
+
+	pack := &models.StarterPack{
+		ID:           uuid.New().String(),
+		UserID:       previous.UserID,
+		Experience:   previous.Experience,
+		Paths:        previous.Paths,
+		Technologies: previous.Technologies,
+		IsActive:     true,
+	}
+	err = tx.QueryRow(ctx, `
+		INSERT INTO starter_packs (id, user_id, experience, paths, technologies, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, TRUE, NOW(), NOW())
+		RETURNING created_at
+	`, pack.ID, pack.UserID, pack.Experience, pack.Paths, pack.Technologies).Scan(&pack.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create starter pack: %w", err)
+	}
+	if err := fillStarterPack(ctx, tx, pack); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit starter pack: %w", err)
+	}
+	return pack, nil
+}
+
+// ArchiveStarterPack deactivates the user's active pack. It returns ErrNotFound
+// when the user has no active pack.
+func (db *Database) ArchiveStarterPack(clerkUserID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.Pool.Exec(ctx, `
+		UPDATE starter_packs sp
+		SET is_active = FALSE, archived_at = NOW(), updated_at = NOW()
+		FROM users u
+		WHERE u.id = sp.user_id AND u.clerk_user_id = $1 AND sp.is_active
+	`, clerkUserID)
+	if err != nil {
+		return fmt.Errorf("failed to archive starter pack: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ResetStarterPackProgress clears the completion state of every challenge in the
+// user's active pack, keeping the same challenges
+func (db *Database) ResetStarterPackProgress(clerkUserID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	pack, err := lockStarterPack(ctx, tx, clerkUserID, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE starter_pack_challenges
+		SET is_completed = FALSE, completed_at = NULL, score = 0
+		WHERE starter_pack_id = $1
+	`, pack.ID)
+	if err != nil {
+		return fmt.Errorf("failed to reset starter pack challenges: %w", err)
+	}
+	_, err = tx.Exec(ctx,
+		"UPDATE starter_packs SET current_progress = 0, updated_at = NOW() WHERE id = $1",
+		pack.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reset starter pack progress: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit starter pack reset: %w", err)
+	}
+	return nil
+}
+
+// completeStarterPackChallenge marks the submission's challenge as completed in
+// the user's active starter packs and refreshes their progress counts. A better
+// passing score on an already completed challenge only raises its score.
+func completeStarterPackChallenge(ctx context.Context, tx pgx.Tx, userID, submissionID string, score int) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE starter_pack_challenges spc
+		SET is_completed = TRUE,
+			completed_at = COALESCE(spc.completed_at, NOW()),
+			score = GREATEST(spc.score, $3)
+		FROM starter_packs sp, submissions s
+		WHERE sp.id = spc.starter_pack_id
+			AND sp.user_id = $1 AND COALESCE(sp.is_active, TRUE)
+			AND s.id = $2 AND s.challenge_id = spc.challenge_id
+	`, userID, submissionID, score)
+	if err != nil {
+		return fmt.Errorf("failed to complete starter pack challenge: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE starter_packs sp
+		SET current_progress = done.n, updated_at = NOW()
+		FROM (
+			SELECT starter_pack_id, COUNT(*) FILTER (WHERE is_completed) AS n
+			FROM starter_pack_challenges
+			GROUP BY starter_pack_id
+		) done
+		WHERE done.starter_pack_id = sp.id
+			AND sp.user_id = $1 AND COALESCE(sp.is_active, TRUE)
+			AND COALESCE(sp.current_progress, 0) <> done.n
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to update starter pack progress: %w", err)
+	}
+	return nil
+}
+
+// starterPackColumns is the column list read by scanStarterPack; expects starter_packs aliased as sp
+const starterPackColumns = `
+	sp.id, COALESCE(sp.experience, ''),
+	COALESCE(sp.paths, '[]'::jsonb), COALESCE(sp.technologies, '[]'::jsonb),
+	COALESCE(sp.current_progress, 0), COALESCE(sp.total_challenges, 0),
+	sp.is_active, COALESCE(sp.created_at, 'epoch'::timestamptz), sp.archived_at`
+
+func scanStarterPack(row pgx.Row) (models.StarterPackResponse, error) {
+	var pack models.StarterPackResponse
+	var paths, technologies models.StringArray
+	err := row.Scan(
+		&pack.ID, &pack.Experience, &paths, &technologies,
+		&pack.CurrentProgress, &pack.TotalChallenges,
+		&pack.IsActive, &pack.CreatedAt, &pack.ArchivedAt,
+	)
+	pack.Paths = paths
+	pack.Technologies = technologies
+	if pack.TotalChallenges > 0 {
+		pack.ProgressPercent = math.Round(1000*float64(pack.CurrentProgress)/float64(pack.TotalChallenges)) / 10
+	}
+	return pack, err
+}
+
+// GetActiveStarterPack returns the user's active starter pack with per-challenge progress
+func (db *Database) GetActiveStarterPack(clerkUserID string) (*models.StarterPackResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pack, err := scanStarterPack(db.Pool.QueryRow(ctx, `
+		SELECT `+starterPackColumns+`
+		FROM starter_packs sp
+		JOIN users u ON u.id = sp.user_id
+		WHERE u.clerk_user_id = $1 AND sp.is_active
+	`, clerkUserID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get starter pack: %w", err)
+	}
+
+	pack.Challenges, err = db.getStarterPackChallenges(ctx, pack.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &pack, nil
+}
+
+// ListStarterPacks returns all of the user's starter packs, active first and then
+// archived packs newest first, each with its challenges and progress
+func (db *Database) ListStarterPacks(clerkUserID string) ([]models.StarterPackResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT `+starterPackColumns+`
+		FROM starter_packs sp
+		JOIN users u ON u.id = sp.user_id
+		WHERE u.clerk_user_id = $1
+		ORDER BY sp.is_active DESC, sp.created_at DESC
+	`, clerkUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query starter packs: %w", err)
+	}
+	defer rows.Close()
+
+	packs := []models.StarterPackResponse{}
+	for rows.Next() {
+		pack, err := scanStarterPack(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan starter pack: %w", err)
+		}
+		packs = append(packs, pack)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read starter packs: %w", err)
+	}
+
+	for i := range packs {
+		packs[i].Challenges, err = db.getStarterPackChallenges(ctx, packs[i].ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return packs, nil
+}
+
+// getStarterPackChallenges returns a pack's challenges in order with their progress
+func (db *Database) getStarterPackChallenges(ctx context.Context, packID string) ([]models.ChallengeWithProgress, error) {
+	rows, err := db.Pool.Query(ctx, `
+		SELECT c.id, c.title, c.difficulty, COALESCE(c.tech_stack, '[]'::jsonb),
+			spc.order_index, spc.is_completed, spc.completed_at, spc.score
+		FROM starter_pack_challenges spc
+		JOIN challenges c ON c.id = spc.challenge_id
+		WHERE spc.starter_pack_id = $1
+		ORDER BY spc.order_index
+	`, packID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query starter pack challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.ChallengeWithProgress{}
+	for rows.Next() {
+		var ch models.ChallengeWithProgress
+		var techStack models.StringArray
+		if err := rows.Scan(
+			&ch.ID, &ch.Title, &ch.Difficulty, &techStack,
+			&ch.OrderIndex, &ch.IsCompleted, &ch.CompletedAt, &ch.Score,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan starter pack challenge: %w", err)
+		}
+		ch.TechStack = techStack
+		challenges = append(challenges, ch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read starter pack challenges: %w", err)
+	}
+	return challenges, nil
+}
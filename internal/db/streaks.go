@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// currentStreakSQL is a user's current streak as of now. A streak whose last
+// active day is before yesterday (in the user's time zone) is already broken,
+// even if the reset job has not caught up yet. Expects users aliased as u.
+// users.timezone is always a name Postgres knows; the users_timezone_known
+// trigger rejects anything else.
+const currentStreakSQL = `CASE
+	WHEN u.last_active_date >= (NOW() AT TIME ZONE COALESCE(u.timezone, 'UTC'))::date - 1
+	THEN COALESCE(u.current_streak, 0) ELSE 0 END`
+
+// GetUserActiveDays returns the distinct local dates, in ascending order, on which
+// a user made a reviewed submission (or any submission when allSubmissions is
+// set), together with the user's current local date
+func (db *Database) GetUserActiveDays(ctx context.Context, userID string, allSubmissions bool) ([]time.Time, time.Time, error) {
+	var today time.Time
+	err := db.Pool.QueryRow(ctx,
+		"SELECT (NOW() AT TIME ZONE COALESCE(timezone, 'UTC'))::date FROM users WHERE id = $1",
+		userID,
+	).Scan(&today)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read user's local date: %w", err)
+	}
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT DISTINCT (s.created_at AT TIME ZONE COALESCE(u.timezone, 'UTC'))::date AS day
+		FROM submissions s
+		JOIN users u ON u.id = s.user_id
+		WHERE s.user_id = $1 AND s.created_at IS NOT NULL
+			AND (s.status = 'reviewed' OR $2)
+		ORDER BY day
+	`, userID, allSubmissions)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to query active days: %w", err)
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to scan active day: %w", err)
+		}
+		days = append(days, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read active days: %w", err)
+	}
+
+	return days, today, nil
+}
+
+// UpdateUserStreak stores a recomputed streak. longest_streak never decreases.
+func (db *Database) UpdateUserStreak(ctx context.Context, userID string, current, longest int, lastActiveDate *time.Time) error {
+	_, err := db.Pool.Exec(ctx, `
+		UPDATE users SET
+			current_streak = $2,
+			longest_streak = GREATEST(COALESCE(longest_streak, 0), $3, $2),
+			last_active_date = $4,
+			updated_at = NOW()
+		WHERE id = $1
+	`, userID, current, longest, lastActiveDate)
+	if err != nil {
+		return fmt.Errorf("failed to update streak: %w", err)
+	}
+	return nil
+}
+
+// ResetBrokenStreaks zeroes current_streak for users whose last active day is
+// before yesterday in their own time zone
+func (db *Database) ResetBrokenStreaks(ctx context.Context) (int64, error) {
+	result, err := db.Pool.Exec(ctx, `
+		UPDATE users u SET current_streak = 0, updated_at = NOW()
+		WHERE u.current_streak > 0 AND (`+currentStreakSQL+`) = 0
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reset broken streaks: %w", err)
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,194 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	// ErrUserNotFound is returned when a Clerk user has no row in users
+	ErrUserNotFound = errors.New("user not found")
+	// ErrChallengeUnavailable is returned when a challenge does not exist or is not published
+	ErrChallengeUnavailable = errors.New("challenge not found or not published")
+)
+
+// SubmissionFilter holds the options for listing a user's submissions
+type SubmissionFilter struct {
+	ChallengeID string
+	Status      models.SubmissionStatus
+	Limit       int
+	Offset      int
+}
+
+// submissionColumns is the column list read by scanSubmission
+const submissionColumns = `
+	s.id, s.challenge_id, s.repo_url, COALESCE(s.branch, 'main'), COALESCE(s.commit_hash, ''),
+	COALESCE(s.status, 'pending'), COALESCE(s.score, 0), COALESCE(s.failure_reason, ''),
+	COALESCE(s.created_at, 'epoch'::timestamptz), COALESCE(s.updated_at, 'epoch'::timestamptz)`
+
+// scanSubmission scans a row selected with submissionColumns
+func scanSubmission(row pgx.Row) (models.SubmissionResponse, error) {
+	var sub models.SubmissionResponse
+	err := row.Scan(
+		&sub.ID, &sub.ChallengeID, &sub.RepoURL, &sub.Branch, &sub.CommitHash,
+		&sub.Status, &sub.Score, &sub.FailureReason, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	return sub, err
+}
+
+// GetUserChallengeSubmissions returns a user's submissions for one challenge, newest first
+func (db *Database) GetUserChallengeSubmissions(clerkUserID, challengeID string) ([]models.SubmissionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT `+submissionColumns+`
+		FROM submissions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.clerk_user_id = $1 AND s.challenge_id = $2
+		ORDER BY s.created_at DESC, s.id DESC
+	`, clerkUserID, challengeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []models.SubmissionResponse{}
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		submissions = append(submissions, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// GetUserIDByClerkID resolves a Clerk user ID to the internal users.id
+func (db *Database) GetUserIDByClerkID(clerkUserID string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var userID string
+	err := db.Pool.QueryRow(ctx,
+		"SELECT id FROM users WHERE clerk_user_id = $1",
+		clerkUserID,
+	).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to find user: %w", err)
+	}
+
+	return userID, nil
+}
+
+// CreateSubmission stores a new pending submission for a published challenge
+func (db *Database) CreateSubmission(clerkUserID, challengeID, repoURL, branch, commitHash string) (*models.SubmissionResponse, error) {
+	userID, err := db.GetUserIDByClerkID(clerkUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var published bool
+	err = db.Pool.QueryRow(ctx,
+		"SELECT COALESCE(is_published, FALSE) FROM challenges WHERE id = $1",
+		challengeID,
+	).Scan(&published)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to find challenge: %w", err)
+	}
+	if !published {
+		return nil, ErrChallengeUnavailable
+	}
+
+	// Store NULL rather than an empty commit hash until one is pinned
+	var commitHashPtr *string
+	if commitHash != "" {
+		commitHashPtr = &commitHash
+	}
+
+	query := `
+		INSERT INTO submissions (id, user_id, challenge_id, repo_url, branch, commit_hash, status, score, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, 0, NOW(), NOW())
+		RETURNING ` + submissionColumns
+
+	sub, err := scanSubmission(db.Pool.QueryRow(ctx, query,
+		uuid.New().String(), userID, challengeID, repoURL, branch, commitHashPtr, string(models.StatusPending),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert submission: %w", err)
+	}
+
+	return &sub, nil
+}
+
+// ListUserSubmissions returns a user's submissions, newest first
+func (db *Database) ListUserSubmissions(clerkUserID string, filter SubmissionFilter) ([]models.SubmissionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT `+submissionColumns+`
+		FROM submissions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.clerk_user_id = $1
+			AND ($2 = '' OR s.challenge_id = $2)
+			AND ($3 = '' OR s.status = $3)
+		ORDER BY s.created_at DESC, s.id DESC
+		LIMIT $4 OFFSET $5
+	`, clerkUserID, filter.ChallengeID, string(filter.Status), filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query submissions: %w", err)
+	}
+	defer rows.Close()
+
+	submissions := []models.SubmissionResponse{}
+	for rows.Next() {
+		sub, err := scanSubmission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan submission: %w", err)
+		}
+		submissions = append(submissions, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read submissions: %w", err)
+	}
+
+	return submissions, nil
+}
+
+// GetUserSubmission returns a single submission if it belongs to the given user
+func (db *Database) GetUserSubmission(clerkUserID, submissionID string) (*models.SubmissionResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sub, err := scanSubmission(db.Pool.QueryRow(ctx, `
+		SELECT `+submissionColumns+`
+		FROM submissions s
+		JOIN users u ON u.id = s.user_id
+		WHERE u.clerk_user_id = $1 AND s.id = $2
+	`, clerkUserID, submissionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get submission: %w", err)
+	}
+
+	return &sub, nil
+}
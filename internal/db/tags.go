@@ -0,0 +1,335 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uncategorizedTags is the group tags without a category are listed under
+const uncategorizedTags = "uncategorized"
+
+var (
+	// ErrTagNameTaken is returned when another tag already has the name (in any case)
+	ErrTagNameTaken = errors.New("tag name taken")
+	// ErrTagSlugTaken is returned when another tag already has the slug
+	ErrTagSlugTaken = errors.New("tag slug taken")
+	// ErrInvalidTagMerge is returned when a tag would be merged into itself
+	ErrInvalidTagMerge = errors.New("invalid tag merge")
+)
+
+// ListTags returns every tag ordered by category and name
+func (db *Database) ListTags() ([]models.Tag, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT id, name, slug, COALESCE(category, ''), COALESCE(color, ''),
+			COALESCE(created_at, 'epoch'::timestamptz)
+		FROM tags
+		ORDER BY category NULLS LAST, name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.Slug, &tag.Category, &tag.Color, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return tags, nil
+}
+
+// tagWithCountColumns selects a tag aliased as t with its published challenge count
+const tagWithCountColumns = `t.id, t.name, t.slug, COALESCE(t.category, ''), COALESCE(t.color, ''),
+	COALESCE(t.created_at, 'epoch'::timestamptz),
+	(SELECT COUNT(*) FROM challenge_tags ct JOIN challenges c ON c.id = ct.challenge_id
+		WHERE ct.tag_id = t.id AND c.is_published = TRUE)`
+
+func scanTagWithCount(row pgx.Row) (*models.TagWithCount, error) {
+	var t models.TagWithCount
+	err := row.Scan(&t.ID, &t.Name, &t.Slug, &t.Category, &t.Color, &t.CreatedAt, &t.ChallengeCount)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to scan tag: %w", err)
+	}
+	return &t, nil
+}
+
+// ListTagsByCategory returns every tag with its published challenge count,
+// grouped by category. Categories and the tags in them are ordered by name, with
+// uncategorized tags last.
+func (db *Database) ListTagsByCategory() ([]models.TagCategoryGroup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rows, err := db.Pool.Query(ctx, `
+		SELECT `+tagWithCountColumns+`
+		FROM tags t
+		ORDER BY NULLIF(t.category, '') NULLS LAST, t.name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	groups := []models.TagCategoryGroup{}
+	for rows.Next() {
+		tag, err := scanTagWithCount(rows)
+		if err != nil {
+			return nil, err
+		}
+		category := tag.Category
+		if category == "" {
+			category = uncategorizedTags
+		}
+		if len(groups) == 0 || groups[len(groups)-1].Category != category {
+			groups = append(groups, models.TagCategoryGroup{Category: category, Tags: []models.TagWithCount{}})
+		}
+		last := &groups[len(groups)-1]
+		last.Tags = append(last.Tags, *tag)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read tags: %w", err)
+	}
+	return groups, nil
+}
+
+// checkTagUnique returns ErrTagNameTaken or ErrTagSlugTaken when a tag other
+// than exceptID already uses name (case-insensitively) or slug. Empty values are
+// not checked.
+func checkTagUnique(ctx context.Context, tx pgx.Tx, exceptID, name, slug string) error {
+	var nameTaken, slugTaken bool
+	err := tx.QueryRow(ctx, `
+		SELECT
+			EXISTS (SELECT 1 FROM tags WHERE lower(name) = lower($2) AND id <> $1),
+			EXISTS (SELECT 1 FROM tags WHERE slug = $3 AND id <> $1)
+	`, exceptID, name, slug).Scan(&nameTaken, &slugTaken)
+	if err != nil {
+		return fmt.Errorf("failed to check tag uniqueness: %w", err)
+	}
+	switch {
+	case nameTaken && name != "":
+		return ErrTagNameTaken
+	case slugTaken && slug != "":
+		return ErrTagSlugTaken
+	}
+	return nil
+}
+
+// freeTagSlug returns base, or base with the lowest numeric suffix that no tag
+// uses yet, e.g. "react-2"
+func freeTagSlug(ctx context.Context, tx pgx.Tx, base string) (string, error) {
+	rows, err := tx.Query(ctx, "SELECT slug FROM tags WHERE slug = $1 OR slug LIKE $1 || '-%'", base)
+	if err != nil {
+		return "", fmt.Errorf("failed to query tag slugs: %w", err)
+	}
+	taken, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return "", fmt.Errorf("failed to read tag slugs: %w", err)
+	}
+
+	used := make(map[string]bool, len(taken))
+	for _, s := range taken {
+		used[s] = true
+	}
+	slug := base
+	for n := 2; used[slug]; n++ {
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+	return slug, nil
+}
+
+// tagConflict maps a unique violation on the tags table to the matching error
+func tagConflict(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if strings.Contains(pgErr.ConstraintName, "slug") {
+			return ErrTagSlugTaken
+		}
+		return ErrTagNameTaken
+	}
+	return err
+}
+
+// stringValue returns *s, or "" for nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// CreateTag adds a tag. Without a slug one is generated from the name, made
+// unique with a numeric suffix if needed; an explicit slug must be free.
+func (db *Database) CreateTag(req models.TagRequest) (*models.TagWithCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	name, slug := stringValue(req.Name), stringValue(req.Slug)
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkTagUnique(ctx, tx, "", name, slug); err != nil {
+		return nil, err
+	}
+	if slug == "" {
+		if slug, err = freeTagSlug(ctx, tx, models.Slugify(name)); err != nil {
+			return nil, err
+		}
+	}
+
+	tag, err := scanTagWithCount(tx.QueryRow(ctx, `
+		INSERT INTO tags AS t (id, name, slug, category, color)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''))
+		RETURNING `+tagWithCountColumns,
+		uuid.New().String(), name, slug, stringValue(req.Category), stringValue(req.Color),
+	))
+	if err != nil {
+		return nil, tagConflict(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, tagConflict(fmt.Errorf("failed to commit tag: %w", err))
+	}
+	return tag, nil
+}
+
+// UpdateTag applies the non-nil fields of req to a tag. Renaming a tag keeps
+// its slug so existing links stay valid.
+func (db *Database) UpdateTag(id string, req models.TagRequest) (*models.TagWithCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := []any{id}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	var sets []string
+	if req.Name != nil {
+		sets = append(sets, "name = "+arg(*req.Name))
+	}
+	if req.Slug != nil {
+		sets = append(sets, "slug = "+arg(*req.Slug))
+	}
+	if req.Category != nil {
+		sets = append(sets, fmt.Sprintf("category = NULLIF(%s, '')", arg(*req.Category)))
+	}
+	if req.Color != nil {
+		sets = append(sets, fmt.Sprintf("color = NULLIF(%s, '')", arg(*req.Color)))
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := checkTagUnique(ctx, tx, id, stringValue(req.Name), stringValue(req.Slug)); err != nil {
+		return nil, err
+	}
+
+	// With nothing to change, the update degenerates into a lookup
+	if len(sets) == 0 {
+		sets = append(sets, "id = id")
+	}
+	query := fmt.Sprintf("UPDATE tags t SET %s WHERE t.id = $1 RETURNING %s", strings.Join(sets, ", "), tagWithCountColumns)
+	tag, err := scanTagWithCount(tx.QueryRow(ctx, query, args...))
+	if err != nil {
+		return nil, tagConflict(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, tagConflict(fmt.Errorf("failed to commit tag: %w", err))
+	}
+	return tag, nil
+}
+
+// MergeTags folds the source tags into the target: every challenge tagged with
+// a source is tagged with the target instead, and the sources are deleted.
+// Returns ErrNotFound if any of the tags does not exist.
+func (db *Database) MergeTags(targetID string, sourceIDs []string) (*models.TagWithCount, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	sources := []string{}
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, ErrInvalidTagMerge
+		}
+		if !slices.Contains(sources, id) {
+			sources = append(sources, id)
+		}
+	}
+	if len(sources) == 0 {
+		return nil, ErrInvalidTagMerge
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock every involved tag so concurrent merges and edits serialize
+	var locked int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM (
+			SELECT id FROM tags WHERE id = $1 OR id = ANY($2) ORDER BY id FOR UPDATE
+		) l
+	`, targetID, sources).Scan(&locked)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock tags: %w", err)
+	}
+	if locked != len(sources)+1 {
+		return nil, ErrNotFound
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO challenge_tags (challenge_id, tag_id)
+		SELECT DISTINCT challenge_id, $1 FROM challenge_tags WHERE tag_id = ANY($2)
+		ON CONFLICT DO NOTHING
+	`, targetID, sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retag challenges: %w", err)
+	}
+
+	// Deleting the sources also removes their challenge_tags rows
+	if _, err := tx.Exec(ctx, "DELETE FROM tags WHERE id = ANY($1)", sources); err != nil {
+		return nil, fmt.Errorf("failed to delete merged tags: %w", err)
+	}
+
+	tag, err := scanTagWithCount(tx.QueryRow(ctx, "SELECT "+tagWithCountColumns+" FROM tags t WHERE t.id = $1", targetID))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit tag merge: %w", err)
+	}
+	return tag, nil
+}
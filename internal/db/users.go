@@ -0,0 +1,170 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// EnsureUser creates a minimal user for a Clerk user that has no row yet, e.g.
+// when a request arrives before the user.created webhook. The webhook upserts on
+// clerk_user_id and fills in the rest of the profile. An email already taken by
+// another account is left out rather than failing the request.
+func (db *Database) EnsureUser(ctx context.Context, clerkUserID, email string) error {
+	for _, e := range []string{email, ""} {
+		result, err := db.Pool.Exec(ctx, `
+			INSERT INTO users (id, clerk_user_id, email, created_at, updated_at)
+			VALUES ($1, $2, NULLIF($3, ''), NOW(), NOW())
+			ON CONFLICT DO NOTHING
+		`, uuid.New().String(), clerkUserID, e)
+		if err != nil {
+			return fmt.Errorf("failed to provision user: %w", err)
+		}
+		if result.RowsAffected() > 0 {
+			return nil
+		}
+
+		var exists bool
+		err = db.Pool.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM users WHERE clerk_user_id = $1)",
+			clerkUserID,
+		).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("failed to look up user: %w", err)
+		}
+		if exists {
+			return nil
+		}
+	}
+	return fmt.Errorf("failed to provision user %s", clerkUserID)
+}
+
+// uniqueViolation is the Postgres error code for a unique constraint violation
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err is a Postgres unique constraint violation
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
+
+// ErrUsernameTaken is returned when another user already has the requested username
+var ErrUsernameTaken = errors.New("username already taken")
+
+// ErrUnknownTimezone is returned when Postgres does not know the requested time
+// zone, which the users_timezone_known trigger enforces
+var ErrUnknownTimezone = errors.New("unknown time zone")
+
+// userColumns is the column list read by scanUser
+const userColumns = `
+	id, clerk_user_id, COALESCE(email, ''), COALESCE(username, ''), COALESCE(display_name, ''),
+	COALESCE(avatar_url, ''), COALESCE(bio, ''), COALESCE(github_username, ''),
+	COALESCE(github_connected, FALSE), COALESCE(onboarding_completed, FALSE),
+	COALESCE(current_streak, 0), COALESCE(longest_streak, 0), last_active_date,
+	COALESCE(timezone, 'UTC'), role, COALESCE(total_score, 0), COALESCE(rank, 0),
+	COALESCE(challenges_completed, 0),
+	COALESCE(created_at, 'epoch'::timestamptz), COALESCE(updated_at, 'epoch'::timestamptz)`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	var u models.User
+	err := row.Scan(
+		&u.ID, &u.ClerkUserID, &u.Email, &u.Username, &u.DisplayName,
+		&u.AvatarURL, &u.Bio, &u.GitHubUsername,
+		&u.GitHubConnected, &u.OnboardingCompleted,
+		&u.CurrentStreak, &u.LongestStreak, &u.LastActiveDate,
+		&u.Timezone, &u.Role, &u.TotalScore, &u.Rank,
+		&u.ChallengesCompleted,
+		&u.CreatedAt, &u.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to scan user: %w", err)
+	}
+	return &u, nil
+}
+
+// GetUserByClerkID returns the user with the given Clerk ID
+func (db *Database) GetUserByClerkID(clerkUserID string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return scanUser(db.Pool.QueryRow(ctx,
+		"SELECT "+userColumns+" FROM users WHERE clerk_user_id = $1",
+		clerkUserID,
+	))
+}
+
+// UpdateUserProfile applies the non-nil fields of update to the user. Empty
+// optional fields are stored as NULL. Usernames are unique regardless of case,
+// which the idx_users_username_lower index enforces against concurrent updates.
+func (db *Database) UpdateUserProfile(clerkUserID string, update models.UpdateProfileRequest) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	args := []any{clerkUserID}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	sets := []string{"updated_at = NOW()"}
+	optional := func(column string, value *string) {
+		if value != nil {
+			sets = append(sets, fmt.Sprintf("%s = NULLIF(%s, '')", column, arg(*value)))
+		}
+	}
+	if update.Username != nil {
+		sets = append(sets, "username = "+arg(*update.Username))
+	}
+	optional("display_name", update.DisplayName)
+	optional("bio", update.Bio)
+	optional("github_username", update.GitHubUsername)
+	if update.Timezone != nil {
+		sets = append(sets, "timezone = "+arg(*update.Timezone))
+	}
+
+	if update.Username != nil {
+		var taken bool
+		err := db.Pool.QueryRow(ctx, `
+			SELECT EXISTS (
+				SELECT 1 FROM users WHERE lower(username) = lower($1) AND clerk_user_id <> $2
+			)
+		`, *update.Username, clerkUserID).Scan(&taken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check username: %w", err)
+		}
+		if taken {
+			return nil, ErrUsernameTaken
+		}
+	}
+
+	query := fmt.Sprintf(
+		"UPDATE users SET %s WHERE clerk_user_id = $1 RETURNING %s",
+		strings.Join(sets, ", "), userColumns,
+	)
+	user, err := scanUser(db.Pool.QueryRow(ctx, query, args...))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch {
+			// A concurrent update can still claim the username between the check and the update
+			case pgErr.Code == uniqueViolation:
+				return nil, ErrUsernameTaken
+			// Go's and Postgres's time zone databases can disagree on rare names
+			case pgErr.ConstraintName == "users_timezone_known":
+				return nil, ErrUnknownTimezone
+			}
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,304 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/config"
+)
+
+var (
+	// ErrInvalidRepository is returned when the repository, branch or commit cannot be found
+	ErrInvalidRepository = errors.New("invalid repository")
+	// ErrRepositoryTooLarge is returned when a checkout exceeds the configured limits
+	ErrRepositoryTooLarge = errors.New("repository too large")
+)
+
+var fullCommitPattern = regexp.MustCompile(`^[0-9a-f]{40}([0-9a-f]{24})?$`)
+
+// sizeCheckInterval is how often the workspace size is checked while git runs
+const sizeCheckInterval = 200 * time.Millisecond
+
+// git stderr fragments that mean retrying will not help
+var invalidRepoMessages = []string{
+	"not found",
+	"does not exist",
+	"does not appear to be a git repository",
+	"could not find remote branch",
+	"couldn't find remote ref",
+	"did not match any file(s) known to git",
+	"reference is not a tree",
+	"not a valid object name",
+	"not our ref",
+	"repository is empty",
+	"invalid refspec",
+	"authentication failed",
+	"could not read username",
+}
+
+// Fetcher checks out submission repositories into throwaway workspaces
+type Fetcher struct {
+	workDir  string
+	maxBytes int64
+	maxFiles int
+	timeout  time.Duration
+}
+
+// Workspace is a checked-out repository. Call Cleanup when done with it.
+type Workspace struct {
+	Dir        string // Root of the working tree
+	CommitHash string // Commit that was checked out
+	root       string
+}
+
+// New creates a fetcher from the configuration, filling in defaults for unset values
+func New(cfg config.Fetcher) *Fetcher {
+	f := &Fetcher{
+		workDir:  cfg.WorkDir,
+		maxBytes: int64(cfg.MaxSizeMB) * 1024 * 1024,
+		maxFiles: cfg.MaxFiles,
+		timeout:  cfg.Timeout,
+	}
+	if f.maxBytes <= 0 {
+		f.maxBytes = 50 * 1024 * 1024
+	}
+	if f.maxFiles <= 0 {
+		f.maxFiles = 5000
+	}
+	if f.timeout <= 0 {
+		f.timeout = 2 * time.Minute
+	}
+	return f
+}
+
+// Fetch clones repoURL at branch (and commitHash, when set) into a new workspace.
+// Any URL git understands is accepted, including file:// URLs and local bare
+// repositories. A full commit hash is fetched on its own; the branch is only used
+// to find abbreviated ones. The resolved commit hash is returned on the workspace.
+func (f *Fetcher) Fetch(ctx context.Context, repoURL, branch, commitHash string) (*Workspace, error) {
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	if branch == "" {
+		branch = "main"
+	}
+
+	root, err := os.MkdirTemp(f.workDir, "devarena-review-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create workspace: %w", err)
+	}
+	ws := &Workspace{Dir: filepath.Join(root, "repo"), root: root}
+
+	if err := f.checkout(ctx, ws, repoURL, branch, commitHash); err != nil {
+		ws.Cleanup()
+		return nil, err
+	}
+
+	if err := f.enforceLimits(ws.Dir); err != nil {
+		ws.Cleanup()
+		return nil, err
+	}
+
+	return ws, nil
+}
+
+// checkout clones the repository and resolves the commit to review. The workspace
+// is watched while git runs, so an oversized repository is aborted mid-download
+// rather than after it has been written to disk.
+func (f *Fetcher) checkout(ctx context.Context, ws *Workspace, repoURL, branch, commitHash string) error {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go f.watchSize(ctx, cancel, ws.root)
+
+	switch {
+	case commitHash == "":
+		// Only the branch tip is needed
+		if _, err := f.git(ctx, ws.root, "clone", "--depth", "1", "--single-branch", "--branch", branch,
+			"--", repoURL, ws.Dir); err != nil {
+			return err
+		}
+	case fullCommitPattern.MatchString(commitHash):
+		// Only the pinned commit is needed, not the history leading up to it
+		if _, err := f.git(ctx, ws.root, "init", "--quiet", "--", ws.Dir); err != nil {
+			return err
+		}
+		if _, err := f.git(ctx, ws.Dir, "remote", "add", "--", "origin", repoURL); err != nil {
+			return err
+		}
+		if _, err := f.git(ctx, ws.Dir, "fetch", "--quiet", "--depth", "1", "--no-tags", "origin", commitHash); err != nil {
+			if errors.Is(err, ErrInvalidRepository) {
+				return fmt.Errorf("%w: commit %s not found", ErrInvalidRepository, commitHash)
+			}
+			return err
+		}
+		if _, err := f.git(ctx, ws.Dir, "checkout", "--detach", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	default:
+		// An abbreviated hash has to be looked up in the branch history; file
+		// contents are only downloaded for the commit that is checked out
+		if _, err := f.git(ctx, ws.root, "clone", "--no-checkout", "--single-branch", "--filter=blob:none",
+			"--branch", branch, "--", repoURL, ws.Dir); err != nil {
+			return err
+		}
+		resolved, err := f.git(ctx, ws.Dir, "rev-parse", "--verify", "--quiet", commitHash+"^{commit}")
+		if err != nil || resolved == "" {
+			return fmt.Errorf("%w: commit %s not found on branch %s", ErrInvalidRepository, commitHash, branch)
+		}
+		if _, err := f.git(ctx, ws.Dir, "checkout", "--detach", resolved); err != nil {
+			return err
+		}
+	}
+
+	head, err := f.git(ctx, ws.Dir, "rev-parse", "HEAD")
+	if err != nil {
+		return err
+	}
+	if !fullCommitPattern.MatchString(head) {
+		return fmt.Errorf("unexpected commit hash %q", head)
+	}
+	if commitHash != "" && !strings.HasPrefix(head, strings.ToLower(commitHash)) {
+		return fmt.Errorf("%w: commit %s resolved to %s", ErrInvalidRepository, commitHash, head)
+	}
+
+	ws.CommitHash = head
+	return nil
+}
+
+// watchSize cancels ctx with ErrRepositoryTooLarge once dir outgrows the size
+// limit. The workspace holds both the packed objects and the checked-out files,
+// so it may use up to twice the limit before the fetch is aborted.
+func (f *Fetcher) watchSize(ctx context.Context, cancel context.CancelCauseFunc, dir string) {
+	ticker := time.NewTicker(sizeCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if diskUsage(dir) > 2*f.maxBytes {
+				cancel(fmt.Errorf("%w: exceeds %d MB", ErrRepositoryTooLarge, f.maxBytes/(1024*1024)))
+				return
+			}
+		}
+	}
+}
+
+// diskUsage sums the sizes of the files under dir. Files that disappear while
+// git is writing are skipped.
+func diskUsage(dir string) int64 {
+	var size int64
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// enforceLimits rejects working trees that exceed the size or file-count limits
+func (f *Fetcher) enforceLimits(dir string) error {
+	var size int64
+	files := 0
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" && path != dir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		files++
+		if files > f.maxFiles {
+			return fmt.Errorf("%w: more than %d files", ErrRepositoryTooLarge, f.maxFiles)
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		if size > f.maxBytes {
+			return fmt.Errorf("%w: exceeds %d MB", ErrRepositoryTooLarge, f.maxBytes/(1024*1024))
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrRepositoryTooLarge) {
+		return fmt.Errorf("failed to inspect workspace: %w", err)
+	}
+	return err
+}
+
+// git runs a sandboxed git command and returns its trimmed stdout. Hooks, prompts,
+// system/global config, the ext:: transport and LFS downloads are all disabled.
+func (f *Fetcher) git(ctx context.Context, dir string, args ...string) (string, error) {
+	base := []string{
+		"-c", "core.hooksPath=/dev/null",
+		"-c", "protocol.ext.allow=never",
+		"-c", "advice.detachedHead=false",
+	}
+	cmd := exec.CommandContext(ctx, "git", append(base, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_TERMINAL_PROMPT=0",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_CONFIG_GLOBAL=/dev/null",
+		"GIT_LFS_SKIP_SMUDGE=1",
+		"GIT_ASKPASS=/bin/true",
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if cause := context.Cause(ctx); errors.Is(cause, ErrRepositoryTooLarge) {
+			return "", cause
+		}
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("git %s timed out: %w", args[0], ctx.Err())
+		}
+		msg := strings.TrimSpace(stderr.String())
+		lower := strings.ToLower(msg)
+		for _, fragment := range invalidRepoMessages {
+			if strings.Contains(lower, fragment) {
+				return "", fmt.Errorf("%w: %s", ErrInvalidRepository, lastLine(msg))
+			}
+		}
+		return "", fmt.Errorf("git %s failed: %s", args[0], lastLine(msg))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Cleanup removes the workspace from disk
+func (w *Workspace) Cleanup() error {
+	if w == nil || w.root == "" {
+		return nil
+	}
+	return os.RemoveAll(w.root)
+}
+
+// lastLine returns the last non-empty line of git's output, which carries the actual error
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,259 @@
+package fetcher
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/config"
+)
+
+// testRepo is a bare repository on local disk with a main branch
+type testRepo struct {
+	t       *testing.T
+	work    string // Non-bare clone commits are made in
+	bare    string
+	commits int
+}
+
+func newTestRepo(t *testing.T) *testRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	r := &testRepo{t: t, work: filepath.Join(dir, "work"), bare: filepath.Join(dir, "repo.git")}
+	r.run(dir, "init", "--quiet", "--bare", "--initial-branch", "main", r.bare)
+	r.run(dir, "clone", "--quiet", r.bare, r.work)
+	r.run(r.work, "checkout", "--quiet", "-B", "main")
+	return r
+}
+
+func (r *testRepo) run(dir string, args ...string) string {
+	r.t.Helper()
+	base := []string{"-c", "user.name=Test", "-c", "user.email=test@example.com", "-c", "commit.gpgsign=false"}
+	cmd := exec.Command("git", append(base, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "GIT_CONFIG_GLOBAL=/dev/null")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// commit writes files into the repository, commits and pushes them, and returns the commit hash
+func (r *testRepo) commit(files map[string][]byte) string {
+	r.t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(r.work, name), content, 0o644); err != nil {
+			r.t.Fatal(err)
+		}
+	}
+	r.commits++
+	r.run(r.work, "add", "--all")
+	r.run(r.work, "commit", "--quiet", "--message", fmt.Sprintf("Commit %d", r.commits))
+	r.run(r.work, "push", "--quiet", "origin", "main")
+	return r.run(r.work, "rev-parse", "HEAD")
+}
+
+func (r *testRepo) url() string {
+	return "file://" + r.bare
+}
+
+func newTestFetcher(t *testing.T, maxSizeMB, maxFiles int) *Fetcher {
+	return New(config.Fetcher{WorkDir: t.TempDir(), MaxSizeMB: maxSizeMB, MaxFiles: maxFiles, Timeout: time.Minute})
+}
+
+func readFile(t *testing.T, ws *Workspace, name string) string {
+	t.Helper()
+	content, err := os.ReadFile(filepath.Join(ws.Dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestFetchBranchTip(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string][]byte{"README.md": []byte("v1")})
+	tip := repo.commit(map[string][]byte{"README.md": []byte("v2")})
+
+	ws, err := newTestFetcher(t, 1, 100).Fetch(context.Background(), repo.url(), "main", "")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	defer ws.Cleanup()
+
+	if ws.CommitHash != tip {
+		t.Errorf("CommitHash = %s, want %s", ws.CommitHash, tip)
+	}
+	if got := readFile(t, ws, "README.md"); got != "v2" {
+		t.Errorf("README.md = %q, want %q", got, "v2")
+	}
+}
+
+func TestFetchPinnedCommit(t *testing.T) {
+	repo := newTestRepo(t)
+	pinned := repo.commit(map[string][]byte{"README.md": []byte("v1")})
+	repo.commit(map[string][]byte{"README.md": []byte("v2")})
+
+	for _, tt := range []struct {
+		name string
+		url  string
+		hash string
+	}{
+		{"full hash", repo.url(), pinned},
+		{"abbreviated hash", repo.url(), pinned[:10]},
+		{"local bare repository path", repo.bare, pinned},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, err := newTestFetcher(t, 1, 100).Fetch(context.Background(), tt.url, "main", tt.hash)
+			if err != nil {
+				t.Fatalf("Fetch: %v", err)
+			}
+			defer ws.Cleanup()
+
+			if ws.CommitHash != pinned {
+				t.Errorf("CommitHash = %s, want %s", ws.CommitHash, pinned)
+			}
+			if got := readFile(t, ws, "README.md"); got != "v1" {
+				t.Errorf("README.md = %q, want %q", got, "v1")
+			}
+		})
+	}
+}
+
+func TestFetchPinnedCommitOnlyDownloadsThatCommit(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string][]byte{"history.txt": []byte("old")})
+	pinned := repo.commit(map[string][]byte{"README.md": []byte("v1")})
+
+	ws, err := newTestFetcher(t, 1, 100).Fetch(context.Background(), repo.url(), "main", pinned)
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	defer ws.Cleanup()
+
+	if count := repo.run(ws.Dir, "rev-list", "--count", "HEAD"); count != "1" {
+		t.Errorf("fetched %s commits, want only the pinned one", count)
+	}
+}
+
+func TestFetchInvalidRepository(t *testing.T) {
+	repo := newTestRepo(t)
+	repo.commit(map[string][]byte{"README.md": []byte("v1")})
+
+	for _, tt := range []struct {
+		name, url, branch, hash string
+	}{
+		{"missing repository", "file://" + filepath.Join(t.TempDir(), "missing.git"), "main", ""},
+		{"missing branch", repo.url(), "develop", ""},
+		{"missing full hash", repo.url(), "main", strings.Repeat("a", 40)},
+		{"missing abbreviated hash", repo.url(), "main", "abcdef1"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFetcher(t, 1, 100)
+			ws, err := f.Fetch(context.Background(), tt.url, tt.branch, tt.hash)
+			if !errors.Is(err, ErrInvalidRepository) {
+				ws.Cleanup()
+				t.Fatalf("Fetch error = %v, want ErrInvalidRepository", err)
+			}
+			assertNoWorkspaces(t, f)
+		})
+	}
+}
+
+func TestFetchTooLarge(t *testing.T) {
+	repo := newTestRepo(t)
+	// Random content does not compress, so the download is as large as the file
+	large := make([]byte, 3*1024*1024)
+	if _, err := rand.Read(large); err != nil {
+		t.Fatal(err)
+	}
+	pinned := repo.commit(map[string][]byte{"large.bin": large})
+
+	for _, tt := range []struct {
+		name, hash string
+	}{
+		{"branch tip", ""},
+		{"pinned commit", pinned},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFetcher(t, 1, 100)
+			ws, err := f.Fetch(context.Background(), repo.url(), "main", tt.hash)
+			if !errors.Is(err, ErrRepositoryTooLarge) {
+				ws.Cleanup()
+				t.Fatalf("Fetch error = %v, want ErrRepositoryTooLarge", err)
+			}
+			assertNoWorkspaces(t, f)
+		})
+	}
+}
+
+func TestFetchTooManyFiles(t *testing.T) {
+	repo := newTestRepo(t)
+	files := map[string][]byte{}
+	for i := range 10 {
+		files[fmt.Sprintf("file%d.txt", i)] = []byte(fmt.Sprint(i))
+	}
+	pinned := repo.commit(files)
+
+	for _, tt := range []struct {
+		name, hash string
+	}{
+		{"branch tip", ""},
+		{"pinned commit", pinned},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newTestFetcher(t, 1, 5)
+			ws, err := f.Fetch(context.Background(), repo.url(), "main", tt.hash)
+			if !errors.Is(err, ErrRepositoryTooLarge) {
+				ws.Cleanup()
+				t.Fatalf("Fetch error = %v, want ErrRepositoryTooLarge", err)
+			}
+			assertNoWorkspaces(t, f)
+		})
+	}
+}
+
+func TestWatchSizeAbortsOversizedWorkspace(t *testing.T) {
+	f := newTestFetcher(t, 1, 100)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pack"), make([]byte, 3*1024*1024), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	go f.watchSize(ctx, cancel, dir)
+
+	select {
+	case <-ctx.Done():
+		if !errors.Is(context.Cause(ctx), ErrRepositoryTooLarge) {
+			t.Errorf("cause = %v, want ErrRepositoryTooLarge", context.Cause(ctx))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watchSize did not abort the oversized workspace")
+	}
+}
+
+// assertNoWorkspaces checks that a failed fetch left nothing behind
+func assertNoWorkspaces(t *testing.T, f *Fetcher) {
+	t.Helper()
+	entries, err := os.ReadDir(f.workDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("workspace not cleaned up: %d entries left in %s", len(entries), f.workDir)
+	}
+}
@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/scoring"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxChallengeTitleLength       = 255
+	maxChallengeDescriptionLength = 20000
+	maxChallengeScore             = 1000
+	maxEstimatedHours             = 200
+	maxRequirements               = 50
+	maxRequirementLength          = 500
+	maxChallengeTechStack         = 20
+	maxTechNameLength             = 50
+	maxChallengeTags              = 20
+	maxCategoryWeight             = 100
+)
+
+// validateChallenge trims and normalizes a challenge create or update request and
+// checks it, returning one error per rejected field. Creating requires a title,
+// description and difficulty.
+func validateChallenge(req *models.ChallengeRequest, create bool) []models.FieldError {
+	var errs []models.FieldError
+	fail := func(field, format string, args ...any) {
+		errs = append(errs, models.FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, s := range []*string{req.Title, req.Description, req.RepoTemplateURL} {
+		if s != nil {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+
+	text := func(field string, value *string, maxLength int) {
+		switch {
+		case value == nil && create, value != nil && *value == "":
+			fail(field, "is required")
+		case value != nil && utf8.RuneCountInString(*value) > maxLength:
+			fail(field, "must be at most %d characters", maxLength)
+		}
+	}
+	text("title", req.Title, maxChallengeTitleLength)
+	text("description", req.Description, maxChallengeDescriptionLength)
+
+	switch {
+	case req.Difficulty == nil && create:
+		fail("difficulty", "is required")
+	case req.Difficulty != nil && !req.Difficulty.IsValid():
+		fail("difficulty", "must be one of %s, %s, %s", models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard)
+	}
+	if req.Type != nil && !req.Type.IsValid() {
+		fail("type", "must be one of %s, %s, %s, %s", models.ChallengeTypeProject, models.ChallengeTypeFeature,
+			models.ChallengeTypeRefactor, models.ChallengeTypeBugfix)
+	}
+	if req.MaxScore != nil && (*req.MaxScore < 1 || *req.MaxScore > maxChallengeScore) {
+		fail("max_score", "must be between 1 and %d", maxChallengeScore)
+	}
+	if req.EstimatedHours != nil && (*req.EstimatedHours < 1 || *req.EstimatedHours > maxEstimatedHours) {
+		fail("estimated_hours", "must be between 1 and %d", maxEstimatedHours)
+	}
+
+	if req.RepoTemplateURL != nil && *req.RepoTemplateURL != "" {
+		repoURL, err := normalizeGitHubRepoURL(*req.RepoTemplateURL)
+		if err != nil {
+			fail("repo_template_url", "%v", err)
+		} else {
+			*req.RepoTemplateURL = repoURL
+		}
+	}
+
+	// Reviews are graded against the requirements, so a list that is sent must not be empty
+	if req.Requirements != nil {
+		requirements := models.Requirements{}
+		for _, r := range *req.Requirements {
+			if r = strings.TrimSpace(r); r != "" {
+				requirements = append(requirements, r)
+			}
+		}
+		*req.Requirements = requirements
+		switch {
+		case len(requirements) == 0:
+			fail("requirements", "must list at least one requirement")
+		case len(requirements) > maxRequirements:
+			fail("requirements", "must list at most %d requirements", maxRequirements)
+		case slices.ContainsFunc(requirements, func(r string) bool { return utf8.RuneCountInString(r) > maxRequirementLength }):
+			fail("requirements", "each requirement must be at most %d characters", maxRequirementLength)
+		}
+	}
+
+	if req.TechStack != nil {
+		techStack := models.TechStack{}
+		for _, t := range *req.TechStack {
+			t = strings.TrimSpace(t)
+			if t != "" && !slices.ContainsFunc(techStack, func(s string) bool { return strings.EqualFold(s, t) }) {
+				techStack = append(techStack, t)
+			}
+		}
+		*req.TechStack = techStack
+		switch {
+		case len(techStack) > maxChallengeTechStack:
+			fail("tech_stack", "must list at most %d technologies", maxChallengeTechStack)
+		case slices.ContainsFunc(techStack, func(t string) bool { return utf8.RuneCountInString(t) > maxTechNameLength }):
+			fail("tech_stack", "each technology must be at most %d characters", maxTechNameLength)
+		}
+	}
+
+	if req.CategoryWeights != nil && len(*req.CategoryWeights) > 0 {
+		positive := false
+		weights := *req.CategoryWeights
+		for _, name := range slices.Sorted(maps.Keys(weights)) {
+			weight := weights[name]
+			if _, ok := scoring.DefaultWeights[name]; !ok {
+				fail("category_weights", "unknown review category %q", name)
+				continue
+			}
+			if weight < 0 || weight > maxCategoryWeight {
+				fail("category_weights", "weight of %q must be between 0 and %d", name, maxCategoryWeight)
+			}
+			positive = positive || weight > 0
+		}
+		if !positive {
+			fail("category_weights", "at least one weight must be positive")
+		}
+	}
+
+	if req.TagIDs != nil && len(*req.TagIDs) > maxChallengeTags {
+		fail("tag_ids", "must list at most %d tags", maxChallengeTags)
+	}
+
+	return errs
+}
+
+// challengeError writes the response for a failed challenge write
+func challengeError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+	case errors.Is(err, db.ErrUnknownTag):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":  "Invalid challenge data",
+			"fields": []models.FieldError{{Field: "tag_ids", Message: "contains a tag that does not exist"}},
+		})
+	case errors.Is(err, db.ErrChallengeIncomplete):
+		c.JSON(http.StatusConflict, gin.H{"error": "Challenge needs at least one requirement before it can be published"})
+	case errors.Is(err, db.ErrChallengeInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only unused drafts can be deleted; unpublish the challenge instead"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to %s challenge", action)})
+	}
+}
+
+// AdminListChallengesHandler returns challenges including drafts
+// Query params: status (draft, published), limit, offset
+func (h *Handlers) AdminListChallengesHandler(c *gin.Context) {
+	filter := db.AdminChallengeFilter{
+		Status: c.Query("status"),
+		Limit:  defaultChallengePageSize,
+	}
+
+	switch filter.Status {
+	case "", db.ChallengeStatusDraft, db.ChallengeStatusPublished:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = min(n, maxChallengePageSize)
+	}
+	if offset := c.Query("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		filter.Offset = n
+	}
+
+	challenges, err := h.DB.ListChallengesForAdmin(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch challenges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"challenges": challenges})
+}
+
+// AdminGetChallengeHandler returns a challenge whether or not it is published
+func (h *Handlers) AdminGetChallengeHandler(c *gin.Context) {
+	challenge, err := h.DB.GetChallengeForAdmin(c.Param("id"))
+	if err != nil {
+		challengeError(c, err, "fetch")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// AdminCreateChallengeHandler creates a challenge as an unpublished draft
+func (h *Handlers) AdminCreateChallengeHandler(c *gin.Context) {
+	var req models.ChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errs := validateChallenge(&req, true); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid challenge data", "fields": errs})
+		return
+	}
+
+	challenge, err := h.DB.CreateChallenge(req)
+	if err != nil {
+		challengeError(c, err, "create")
+		return
+	}
+
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// AdminUpdateChallengeHandler edits a challenge's content, tags or scoring
+func (h *Handlers) AdminUpdateChallengeHandler(c *gin.Context) {
+	var req models.ChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errs := validateChallenge(&req, false); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid challenge data", "fields": errs})
+		return
+	}
+
+	challenge, err := h.DB.UpdateChallenge(c.Param("id"), req)
+	if err != nil {
+		challengeError(c, err, "update")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// AdminPublishChallengeHandler makes a draft visible to users
+func (h *Handlers) AdminPublishChallengeHandler(c *gin.Context) {
+	h.setChallengePublished(c, true)
+}
+
+// AdminUnpublishChallengeHandler hides a challenge from users again
+func (h *Handlers) AdminUnpublishChallengeHandler(c *gin.Context) {
+	h.setChallengePublished(c, false)
+}
+
+func (h *Handlers) setChallengePublished(c *gin.Context, published bool) {
+	challenge, err := h.DB.SetChallengePublished(c.Param("id"), published)
+	if err != nil {
+		challengeError(c, err, "update")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// AdminDeleteChallengeHandler deletes a draft nobody has worked on
+func (h *Handlers) AdminDeleteChallengeHandler(c *gin.Context) {
+	if err := h.DB.DeleteChallenge(c.Param("id")); err != nil {
+		challengeError(c, err, "delete")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
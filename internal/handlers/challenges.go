@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/scoring"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultChallengePageSize = 20
+	maxChallengePageSize     = 100
+)
+
+// ListChallengesHandler returns the published challenge catalog
+// Query params: difficulty, type, tag (slug), tech, sort, limit, cursor
+func (h *Handlers) ListChallengesHandler(c *gin.Context) {
+	filter := db.ChallengeFilter{
+		Difficulty: models.Difficulty(c.Query("difficulty")),
+		Type:       models.ChallengeType(c.Query("type")),
+		TagSlug:    c.Query("tag"),
+		TechStack:  c.Query("tech"),
+		Sort:       c.DefaultQuery("sort", db.ChallengeSortNewest),
+		Limit:      defaultChallengePageSize,
+		Cursor:     c.Query("cursor"),
+	}
+
+	if filter.Difficulty != "" && !filter.Difficulty.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid difficulty"})
+		return
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge type"})
+		return
+	}
+	if !db.IsValidChallengeSort(filter.Sort) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort"})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = min(n, maxChallengePageSize)
+	}
+
+	// Personalize IsSolved when the caller is signed in
+	if userID, ok := middleware.GetUserID(c); ok {
+		filter.ClerkUserID = userID
+	}
+
+	page, err := h.DB.ListChallenges(filter)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch challenges"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetChallengeHandler returns a published challenge with its requirements and tags,
+// plus the caller's own submissions and best score when they are signed in
+func (h *Handlers) GetChallengeHandler(c *gin.Context) {
+	challenge, err := h.DB.GetPublishedChallenge(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch challenge"})
+		return
+	}
+
+	response := models.ChallengeDetailResponse{
+		Challenge:     *challenge,
+		MySubmissions: []models.SubmissionResponse{},
+	}
+
+	if userID, ok := middleware.GetUserID(c); ok {
+		submissions, err := h.DB.GetUserChallengeSubmissions(userID, challenge.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+			return
+		}
+		response.MySubmissions = submissions
+
+		for _, sub := range submissions {
+			if sub.Status != models.StatusReviewed {
+				continue
+			}
+			if scoring.Passed(sub.Score, challenge.MaxScore) {
+				response.IsSolved = true
+			}
+			if response.BestScore == nil || sub.Score > *response.BestScore {
+				score := sub.Score
+				response.BestScore = &score
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -5,17 +5,19 @@ import (
 
 	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
 	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/streaks"
 	"github.com/gin-gonic/gin"
 )
 
 // Handlers holds dependencies for HTTP handlers
 type Handlers struct {
-	DB *db.Database
+	DB      *db.Database
+	Streaks *streaks.Tracker
 }
 
 // NewHandlers creates a new Handlers instance
-func NewHandlers(database *db.Database) *Handlers {
-	return &Handlers{DB: database}
+func NewHandlers(database *db.Database, tracker *streaks.Tracker) *Handlers {
+	return &Handlers{DB: database, Streaks: tracker}
 }
 
 // HealthHandler checks database connection health
@@ -31,23 +33,42 @@ func (h *Handlers) OnboardingHandler(c *gin.Context) {
 		return
 	}
 
-	var onboardingData struct {
-		Experience   string   `json:"experience"`
-		Paths        []string `json:"paths"`
-		Technologies []string `json:"technologies"`
-	}
-
+	var onboardingData db.OnboardingData
 
 	if err := c.ShouldBindJSON(&onboardingData); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
 		return
 	}
 
+	options, err := h.onboardingOptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load onboarding options"})
+		return
+	}
+	if errs := validateOnboarding(&onboardingData, options); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid onboarding data", "fields": errs})
+		return
+	}
+
 	// Save onboarding data to database
 	if err := h.DB.SaveOnboardingData(userID, onboardingData); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save onboarding data"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Onboarding data saved successfully"})
+	// Pick the starter pack's challenges from the answers just saved
+	pack, err := h.DB.GenerateStarterPack(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate starter pack"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Onboarding data saved successfully",
+		"starter_pack": gin.H{
+			"id":               pack.ID,
+			"challenge_ids":    pack.Challenges,
+			"total_challenges": pack.TotalChallenges,
+		},
+	})
 }
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLeaderboardPageSize = 50
+	maxLeaderboardPageSize     = 100
+	defaultLeaderboardRadius   = 2
+	maxLeaderboardRadius       = 10
+)
+
+// parseLeaderboardFilter reads a models.LeaderboardFilter from the query string
+// Query params: period, tech_stack, difficulty, limit, offset
+func parseLeaderboardFilter(c *gin.Context) (models.LeaderboardFilter, error) {
+	filter := models.LeaderboardFilter{
+		Period:     c.DefaultQuery("period", models.PeriodAllTime),
+		TechStack:  c.Query("tech_stack"),
+		Difficulty: c.Query("difficulty"),
+		Limit:      defaultLeaderboardPageSize,
+	}
+
+	switch filter.Period {
+	case models.PeriodAllTime, models.PeriodWeekly, models.PeriodMonthly:
+	default:
+		return filter, fmt.Errorf("invalid period")
+	}
+	if filter.Difficulty != "" && !models.Difficulty(filter.Difficulty).IsValid() {
+		return filter, fmt.Errorf("invalid difficulty")
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return filter, fmt.Errorf("invalid limit")
+		}
+		filter.Limit = min(n, maxLeaderboardPageSize)
+	}
+	if offset := c.Query("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return filter, fmt.Errorf("invalid offset")
+		}
+		filter.Offset = n
+	}
+
+	return filter, nil
+}
+
+// LeaderboardHandler returns a page of the leaderboard
+func (h *Handlers) LeaderboardHandler(c *gin.Context) {
+	filter, err := parseLeaderboardFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	leaderboard, err := h.DB.GetLeaderboard(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard"})
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
+
+// MyLeaderboardPositionHandler returns the caller's rank and the users around them
+// Query params: the leaderboard filters plus radius (neighbors on each side)
+func (h *Handlers) MyLeaderboardPositionHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filter, err := parseLeaderboardFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	// Paging does not apply to a position lookup
+	filter.Limit, filter.Offset = 0, 0
+
+	radius := defaultLeaderboardRadius
+	if r := c.Query("radius"); r != "" {
+		n, err := strconv.Atoi(r)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid radius"})
+			return
+		}
+		radius = min(n, maxLeaderboardRadius)
+	}
+
+	position, err := h.DB.GetLeaderboardPosition(userID, filter, radius)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard position"})
+		return
+	}
+
+	c.JSON(http.StatusOK, position)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const maxOnboardingTechnologies = 20
+
+// onboardingOptions builds the onboarding vocabulary; technologies come from the tags table
+func (h *Handlers) onboardingOptions() (*models.OnboardingOptions, error) {
+	tags, err := h.DB.ListTags()
+	if err != nil {
+		return nil, err
+	}
+
+	technologies := make([]models.OnboardingOption, 0, len(tags))
+	for _, tag := range tags {
+		technologies = append(technologies, models.OnboardingOption{
+			Value:    tag.Slug,
+			Label:    tag.Name,
+			Category: tag.Category,
+		})
+	}
+
+	return &models.OnboardingOptions{
+		Experience:   models.ExperienceOptions,
+		Paths:        models.PathOptions,
+		Technologies: technologies,
+	}, nil
+}
+
+// findOption returns the value of the option matching s by value or label, ignoring case
+func findOption(options []models.OnboardingOption, s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	for _, opt := range options {
+		if strings.EqualFold(opt.Value, s) || strings.EqualFold(opt.Label, s) {
+			return opt.Value, true
+		}
+	}
+	return "", false
+}
+
+// validateOnboarding checks the answers against the vocabulary and rewrites them
+// to canonical option values, dropping duplicates. It returns one error per
+// rejected field.
+func validateOnboarding(data *db.OnboardingData, options *models.OnboardingOptions) []models.FieldError {
+	var errs []models.FieldError
+
+	if strings.TrimSpace(data.Experience) == "" {
+		errs = append(errs, models.FieldError{Field: "experience", Message: "is required"})
+	} else if value, ok := findOption(options.Experience, data.Experience); ok {
+		data.Experience = value
+	} else {
+		errs = append(errs, models.FieldError{Field: "experience", Message: fmt.Sprintf("unknown experience level %q", data.Experience)})
+	}
+
+	// canonicalize validates each answer of a multiple-choice field
+	canonicalize := func(field string, answers []string, options []models.OnboardingOption) []string {
+		var values []string
+		for i, answer := range answers {
+			value, ok := findOption(options, answer)
+			if !ok {
+				errs = append(errs, models.FieldError{
+					Field:   fmt.Sprintf("%s[%d]", field, i),
+					Message: fmt.Sprintf("unknown value %q", answer),
+				})
+				continue
+			}
+			if !slices.Contains(values, value) {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	if len(data.Paths) == 0 {
+		errs = append(errs, models.FieldError{Field: "paths", Message: "at least one path is required"})
+	}
+	data.Paths = canonicalize("paths", data.Paths, options.Paths)
+
+	if len(data.Technologies) > maxOnboardingTechnologies {
+		errs = append(errs, models.FieldError{
+			Field:   "technologies",
+			Message: fmt.Sprintf("at most %d technologies can be selected", maxOnboardingTechnologies),
+		})
+	}
+	data.Technologies = canonicalize("technologies", data.Technologies, options.Technologies)
+	if data.Technologies == nil {
+		data.Technologies = []string{}
+	}
+
+	return errs
+}
+
+// OnboardingOptionsHandler returns the experience levels, paths and technologies
+// accepted by OnboardingHandler
+func (h *Handlers) OnboardingOptionsHandler(c *gin.Context) {
+	options, err := h.onboardingOptions()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch onboarding options"})
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
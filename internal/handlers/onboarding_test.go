@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+var testOnboardingOptions = &models.OnboardingOptions{
+	Experience: models.ExperienceOptions,
+	Paths:      models.PathOptions,
+	Technologies: []models.OnboardingOption{
+		{Value: "go", Label: "Go", Category: "Language"},
+		{Value: "react", Label: "React", Category: "Frontend"},
+		{Value: "nodejs", Label: "Node.js", Category: "Backend"},
+	},
+}
+
+func TestValidateOnboarding(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		data       db.OnboardingData
+		want       db.OnboardingData // Canonical answers, checked when no errors are expected
+		wantFields []string
+	}{
+		{
+			name: "canonical values",
+			data: db.OnboardingData{Experience: "beginner", Paths: []string{"backend"}, Technologies: []string{"go"}},
+			want: db.OnboardingData{Experience: "beginner", Paths: []string{"backend"}, Technologies: []string{"go"}},
+		},
+		{
+			name: "labels and case are canonicalized",
+			data: db.OnboardingData{
+				Experience:   "  Intermediate ",
+				Paths:        []string{"Full Stack", "AI / ML"},
+				Technologies: []string{"NODE.JS", "React"},
+			},
+			want: db.OnboardingData{
+				Experience:   models.ExperienceIntermediate,
+				Paths:        []string{models.PathFullstack, models.PathAI},
+				Technologies: []string{"nodejs", "react"},
+			},
+		},
+		{
+			name: "duplicates are dropped keeping the first",
+			data: db.OnboardingData{
+				Experience:   "expert",
+				Paths:        []string{"frontend", "Frontend", "mobile", "FRONTEND"},
+				Technologies: []string{"go", "Go", "react", "go"},
+			},
+			want: db.OnboardingData{
+				Experience:   models.ExperienceExpert,
+				Paths:        []string{models.PathFrontend, models.PathMobile},
+				Technologies: []string{"go", "react"},
+			},
+		},
+		{
+			name: "no technologies",
+			data: db.OnboardingData{Experience: "advanced", Paths: []string{"ai"}},
+			want: db.OnboardingData{Experience: models.ExperienceAdvanced, Paths: []string{models.PathAI}, Technologies: []string{}},
+		},
+		{
+			name:       "missing answers",
+			data:       db.OnboardingData{Experience: " "},
+			wantFields: []string{"experience", "paths"},
+		},
+		{
+			name:       "unknown experience",
+			data:       db.OnboardingData{Experience: "guru", Paths: []string{"backend"}},
+			wantFields: []string{"experience"},
+		},
+		{
+			name: "unknown answers are reported by index",
+			data: db.OnboardingData{
+				Experience:   "beginner",
+				Paths:        []string{"backend", "devops", "frontend", "gamedev"},
+				Technologies: []string{"cobol", "go"},
+			},
+			wantFields: []string{"paths[1]", "paths[3]", "technologies[0]"},
+		},
+		{
+			name: "too many technologies",
+			data: db.OnboardingData{
+				Experience:   "beginner",
+				Paths:        []string{"backend"},
+				Technologies: slices.Repeat([]string{"go"}, maxOnboardingTechnologies+1),
+			},
+			wantFields: []string{"technologies"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data := tt.data
+			errs := validateOnboarding(&data, testOnboardingOptions)
+
+			var fields []string
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			if !slices.Equal(fields, tt.wantFields) {
+				t.Fatalf("error fields = %q, want %q (errors: %+v)", fields, tt.wantFields, errs)
+			}
+			if len(tt.wantFields) > 0 {
+				return
+			}
+
+			if data.Experience != tt.want.Experience ||
+				!slices.Equal(data.Paths, tt.want.Paths) ||
+				!slices.Equal(data.Technologies, tt.want.Technologies) || data.Technologies == nil {
+				t.Errorf("validated data = %+v, want %+v", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateOnboardingUnknownValueMessage(t *testing.T) {
+	data := db.OnboardingData{Experience: "beginner", Paths: []string{"devops"}}
+	errs := validateOnboarding(&data, testOnboardingOptions)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `"devops"`) {
+		t.Errorf("errors = %+v, want one naming \"devops\"", errs)
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	maxBioLength         = 500
+	maxDisplayNameLength = 100
+)
+
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{2,29}$`)
+
+// validateProfileUpdate trims the requested changes and checks them, returning
+// one error per rejected field
+func validateProfileUpdate(req *models.UpdateProfileRequest) []models.FieldError {
+	var errs []models.FieldError
+	trim := func(s *string) {
+		if s != nil {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+	trim(req.Username)
+	trim(req.DisplayName)
+	trim(req.Bio)
+	trim(req.GitHubUsername)
+	trim(req.Timezone)
+
+	if req.Username != nil && !usernamePattern.MatchString(*req.Username) {
+		errs = append(errs, models.FieldError{
+			Field:   "username",
+			Message: "must be 3-30 letters, digits, '_' or '-', starting with a letter or digit",
+		})
+	}
+	if req.DisplayName != nil && utf8.RuneCountInString(*req.DisplayName) > maxDisplayNameLength {
+		errs = append(errs, models.FieldError{
+			Field:   "display_name",
+			Message: fmt.Sprintf("must be at most %d characters", maxDisplayNameLength),
+		})
+	}
+	if req.Bio != nil && utf8.RuneCountInString(*req.Bio) > maxBioLength {
+		errs = append(errs, models.FieldError{
+			Field:   "bio",
+			Message: fmt.Sprintf("must be at most %d characters", maxBioLength),
+		})
+	}
+	if req.GitHubUsername != nil && *req.GitHubUsername != "" && !githubOwnerPattern.MatchString(*req.GitHubUsername) {
+		errs = append(errs, models.FieldError{Field: "github_username", Message: "is not a valid GitHub username"})
+	}
+	if req.Timezone != nil {
+		if _, err := time.LoadLocation(*req.Timezone); err != nil || *req.Timezone == "" || *req.Timezone == "Local" {
+			errs = append(errs, models.FieldError{Field: "timezone", Message: "must be an IANA time zone such as Europe/Berlin"})
+		}
+	}
+
+	return errs
+}
+
+// GetMeHandler returns the caller's profile
+func (h *Handlers) GetMeHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	user, err := h.DB.GetUserByClerkID(userID)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateMeHandler edits the caller's username, display name, bio, GitHub username
+// and time zone
+func (h *Handlers) UpdateMeHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errs := validateProfileUpdate(&req); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid profile data", "fields": errs})
+		return
+	}
+
+	user, err := h.DB.UpdateUserProfile(userID, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrUsernameTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": "Username is already taken"})
+		case errors.Is(err, db.ErrUnknownTimezone):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error":  "Invalid profile data",
+				"fields": []models.FieldError{{Field: "timezone", Message: "must be an IANA time zone such as Europe/Berlin"}},
+			})
+		case errors.Is(err, db.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update profile"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
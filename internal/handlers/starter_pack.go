@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/gin-gonic/gin"
+)
+
+// GetStarterPackHandler returns the caller's active starter pack with progress
+func (h *Handlers) GetStarterPackHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	pack, err := h.DB.GetActiveStarterPack(userID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Starter pack not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch starter pack"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pack)
+}
+
+// ListStarterPacksHandler returns the caller's active and archived starter packs
+func (h *Handlers) ListStarterPacksHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	packs, err := h.DB.ListStarterPacks(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch starter packs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"starter_packs": packs})
+}
+
+// RegenerateStarterPackHandler archives the caller's active pack and creates a
+// new one from their latest onboarding answers
+func (h *Handlers) RegenerateStarterPackHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if _, err := h.DB.RegenerateStarterPack(userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Complete onboarding to get a starter pack"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to regenerate starter pack"})
+		return
+	}
+
+	pack, err := h.DB.GetActiveStarterPack(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch starter pack"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, pack)
+}
+
+// ArchiveStarterPackHandler deactivates the caller's active pack
+func (h *Handlers) ArchiveStarterPackHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.DB.ArchiveStarterPack(userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Starter pack not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to archive starter pack"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Starter pack archived"})
+}
+
+// ResetStarterPackHandler clears the progress of the caller's active pack
+func (h *Handlers) ResetStarterPackHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	if err := h.DB.ResetStarterPackProgress(userID); err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Starter pack not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reset starter pack"})
+		return
+	}
+
+	pack, err := h.DB.GetActiveStarterPack(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch starter pack"})
+		return
+	}
+
+	c.JSON(http.StatusOK, pack)
+}
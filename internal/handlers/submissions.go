@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultBranch             = "main"
+	defaultSubmissionPageSize = 20
+	maxSubmissionPageSize     = 100
+)
+
+var (
+	githubOwnerPattern = regexp.MustCompile(`^[A-Za-z0-9](?:[A-Za-z0-9-]{0,38})$`)
+	githubRepoPattern  = regexp.MustCompile(`^[A-Za-z0-9._-]{1,100}$`)
+	commitHashPattern  = regexp.MustCompile(`^[0-9a-fA-F]{7,64}$`)
+)
+
+// normalizeGitHubRepoURL validates that rawURL points at a GitHub repository and
+// returns it in the canonical https://github.com/<owner>/<repo> form
+func normalizeGitHubRepoURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", fmt.Errorf("URL must use https")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host != "github.com" && host != "www.github.com" {
+		return "", fmt.Errorf("URL must point to github.com")
+	}
+	if u.Port() != "" || u.User != nil {
+		return "", fmt.Errorf("URL must not contain a port or credentials")
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("URL must be of the form https://github.com/<owner>/<repo>")
+	}
+
+	owner := parts[0]
+	repo := strings.TrimSuffix(parts[1], ".git")
+	if !githubOwnerPattern.MatchString(owner) {
+		return "", fmt.Errorf("invalid GitHub owner")
+	}
+	if !githubRepoPattern.MatchString(repo) || repo == "." || repo == ".." {
+		return "", fmt.Errorf("invalid GitHub repository name")
+	}
+
+	return fmt.Sprintf("https://github.com/%s/%s", owner, repo), nil
+}
+
+// isValidBranchName applies a conservative subset of git's ref name rules
+func isValidBranchName(branch string) bool {
+	if branch == "" || len(branch) > 100 {
+		return false
+	}
+	if strings.HasPrefix(branch, "-") || strings.HasPrefix(branch, "/") || strings.HasSuffix(branch, "/") ||
+		strings.HasSuffix(branch, ".lock") || strings.HasSuffix(branch, ".") {
+		return false
+	}
+	if strings.Contains(branch, "..") || strings.Contains(branch, "//") || strings.Contains(branch, "@{") {
+		return false
+	}
+	for _, r := range branch {
+		if r <= ' ' || r == 0x7f || strings.ContainsRune("~^:?*[\\", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// CreateSubmissionHandler records a GitHub repository submission for a challenge
+func (h *Handlers) CreateSubmissionHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req models.SubmissionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	repoURL, err := normalizeGitHubRepoURL(req.RepoURL)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid repo_url: %v", err)})
+		return
+	}
+
+	branch := strings.TrimSpace(req.Branch)
+	if branch == "" {
+		branch = defaultBranch
+	}
+	if !isValidBranchName(branch) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid branch name"})
+		return
+	}
+
+	commitHash := strings.ToLower(strings.TrimSpace(req.CommitHash))
+	if commitHash != "" && !commitHashPattern.MatchString(commitHash) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid commit_hash"})
+		return
+	}
+
+	submission, err := h.DB.CreateSubmission(userID, req.ChallengeID, repoURL, branch, commitHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case errors.Is(err, db.ErrChallengeUnavailable):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create submission"})
+		}
+		return
+	}
+
+	// Reviewed submissions update the streak from the review worker
+	if h.Streaks != nil && h.Streaks.CountsAllSubmissions() {
+		h.recordStreak(c, userID)
+	}
+
+	c.JSON(http.StatusCreated, submission)
+}
+
+// ListSubmissionsHandler returns the caller's submissions
+// Query params: challenge_id, status, limit, offset
+func (h *Handlers) ListSubmissionsHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	filter := db.SubmissionFilter{
+		ChallengeID: c.Query("challenge_id"),
+		Status:      models.SubmissionStatus(c.Query("status")),
+		Limit:       defaultSubmissionPageSize,
+	}
+
+	switch filter.Status {
+	case "", models.StatusPending, models.StatusReviewing, models.StatusReviewed, models.StatusFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+		return
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = min(n, maxSubmissionPageSize)
+	}
+	if offset := c.Query("offset"); offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset"})
+			return
+		}
+		filter.Offset = n
+	}
+
+	submissions, err := h.DB.ListUserSubmissions(userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"submissions": submissions})
+}
+
+// GetSubmissionHandler returns one of the caller's submissions
+func (h *Handlers) GetSubmissionHandler(c *gin.Context) {
+	userID, exists := middleware.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	submission, err := h.DB.GetUserSubmission(userID, c.Param("id"))
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		return
+	}
+
+	c.JSON(http.StatusOK, submission)
+}
+
+// recordStreak updates the caller's streak after new activity. Failures are only
+// logged since the activity itself has already been saved.
+func (h *Handlers) recordStreak(c *gin.Context, clerkUserID string) {
+	userID, err := h.DB.GetUserIDByClerkID(clerkUserID)
+	if err == nil {
+		err = h.Streaks.Record(c.Request.Context(), userID)
+	}
+	if err != nil {
+		log.Printf("Failed to update streak for user %s: %v", clerkUserID, err)
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const maxTagFieldLength = 100
+
+var (
+	tagSlugPattern  = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+	tagColorPattern = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+)
+
+// validateTag trims and normalizes a tag create or update request and checks it,
+// returning one error per rejected field. Creating requires a name.
+func validateTag(req *models.TagRequest, create bool) []models.FieldError {
+	var errs []models.FieldError
+	for _, s := range []*string{req.Name, req.Slug, req.Category, req.Color} {
+		if s != nil {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+	if req.Category != nil {
+		*req.Category = strings.ToLower(*req.Category)
+	}
+
+	switch {
+	case req.Name == nil && create, req.Name != nil && *req.Name == "":
+		errs = append(errs, models.FieldError{Field: "name", Message: "is required"})
+	case req.Name != nil && utf8.RuneCountInString(*req.Name) > maxTagFieldLength:
+		errs = append(errs, models.FieldError{Field: "name", Message: fmt.Sprintf("must be at most %d characters", maxTagFieldLength)})
+	case create && (req.Slug == nil || *req.Slug == "") && models.Slugify(*req.Name) == "":
+		errs = append(errs, models.FieldError{Field: "name", Message: "must contain letters or digits to generate a slug from"})
+	}
+
+	// An empty slug on create means "generate one"; an update must not clear it
+	if req.Slug != nil && (*req.Slug != "" || !create) {
+		if len(*req.Slug) > maxTagFieldLength || !tagSlugPattern.MatchString(*req.Slug) {
+			errs = append(errs, models.FieldError{
+				Field:   "slug",
+				Message: "must be lowercase letters and digits separated by single '-'",
+			})
+		}
+	}
+	if req.Category != nil && utf8.RuneCountInString(*req.Category) > maxTagFieldLength {
+		errs = append(errs, models.FieldError{Field: "category", Message: fmt.Sprintf("must be at most %d characters", maxTagFieldLength)})
+	}
+	if req.Color != nil && *req.Color != "" && !tagColorPattern.MatchString(*req.Color) {
+		errs = append(errs, models.FieldError{Field: "color", Message: "must be a hex color such as #61dafb"})
+	}
+
+	return errs
+}
+
+// tagError writes the response for a failed tag write
+func tagError(c *gin.Context, err error, action string) {
+	switch {
+	case errors.Is(err, db.ErrTagNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "A tag with this name already exists"})
+	case errors.Is(err, db.ErrTagSlugTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "A tag with this slug already exists"})
+	case errors.Is(err, db.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Tag not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to %s tag", action)})
+	}
+}
+
+// ListTagsHandler returns all tags grouped by category, with the number of
+// published challenges using each
+func (h *Handlers) ListTagsHandler(c *gin.Context) {
+	groups, err := h.DB.ListTagsByCategory()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": groups})
+}
+
+// CreateTagHandler adds a tag, generating its slug from the name when none is given
+func (h *Handlers) CreateTagHandler(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errs := validateTag(&req, true); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid tag data", "fields": errs})
+		return
+	}
+
+	tag, err := h.DB.CreateTag(req)
+	if err != nil {
+		tagError(c, err, "create")
+		return
+	}
+
+	c.JSON(http.StatusCreated, tag)
+}
+
+// UpdateTagHandler edits a tag's name, slug, category or color
+func (h *Handlers) UpdateTagHandler(c *gin.Context) {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if errs := validateTag(&req, false); len(errs) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Invalid tag data", "fields": errs})
+		return
+	}
+
+	tag, err := h.DB.UpdateTag(c.Param("id"), req)
+	if err != nil {
+		tagError(c, err, "update")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
+
+// MergeTagsHandler folds the tags listed in source_ids into the tag in the path,
+// moving their challenges over and deleting them
+func (h *Handlers) MergeTagsHandler(c *gin.Context) {
+	var req models.MergeTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+
+	tag, err := h.DB.MergeTags(c.Param("id"), req.SourceIDs)
+	if err != nil {
+		if errors.Is(err, db.ErrInvalidTagMerge) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "source_ids must list other tags than the target"})
+			return
+		}
+		tagError(c, err, "merge")
+		return
+	}
+
+	c.JSON(http.StatusOK, tag)
+}
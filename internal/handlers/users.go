@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultActivityPageSize = 20
+	maxActivityPageSize     = 100
+)
+
+// GetUserProfileHandler returns a user's public profile by username
+func (h *Handlers) GetUserProfileHandler(c *gin.Context) {
+	profile, err := h.DB.GetPublicProfile(c.Param("username"))
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetUserActivityHandler returns a user's daily activity calendar and timeline
+// Query params: from, to (YYYY-MM-DD in the user's time zone), limit, cursor
+func (h *Handlers) GetUserActivityHandler(c *gin.Context) {
+	filter := db.ActivityFilter{
+		Limit:  defaultActivityPageSize,
+		Cursor: c.Query("cursor"),
+	}
+
+	dates := []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &filter.From}, {"to", &filter.To}}
+	for _, d := range dates {
+		if value := c.Query(d.name); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s date, expected YYYY-MM-DD", d.name)})
+				return
+			}
+			*d.dst = date
+		}
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+		filter.Limit = min(n, maxActivityPageSize)
+	}
+
+	activity, err := h.DB.GetUserActivity(c.Param("username"), filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrUserNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		case errors.Is(err, db.ErrInvalidActivityRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid date range: from must not be after to and span at most %d days", db.MaxActivityRangeDays)})
+		case errors.Is(err, db.ErrInvalidCursor):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, activity)
+}
+
+// UpdateUserRoleHandler changes a user's role
+func (h *Handlers) UpdateUserRoleHandler(c *gin.Context) {
+	var req models.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+		return
+	}
+	if !req.Role.IsValid() {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Invalid role data",
+			"fields": []models.FieldError{{
+				Field:   "role",
+				Message: fmt.Sprintf("must be one of %s, %s, %s", models.RoleUser, models.RoleReviewer, models.RoleAdmin),
+			}},
+		})
+		return
+	}
+
+	user, err := h.DB.UpdateUserRole(c.Param("username"), req.Role)
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
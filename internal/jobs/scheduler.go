@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// Func is a unit of periodic work. It should return promptly once ctx is cancelled.
+type Func func(ctx context.Context) error
+
+type job struct {
+	name     string
+	interval time.Duration
+	fn       Func
+}
+
+// Scheduler runs named jobs on fixed intervals. Runs of the same job never overlap.
+type Scheduler struct {
+	jobs     []job
+	stop     context.CancelFunc
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewScheduler creates an empty scheduler
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add registers a job. Jobs with a non-positive interval are ignored, so a zero
+// interval in config disables the job. Add must be called before Start.
+func (s *Scheduler) Add(name string, interval time.Duration, fn Func) {
+	if interval <= 0 {
+		log.Printf("Job %s disabled", name)
+		return
+	}
+	s.jobs = append(s.jobs, job{name: name, interval: interval, fn: fn})
+}
+
+// Start runs every job once immediately and then on its interval until Shutdown
+func (s *Scheduler) Start() {
+	ctx, stop := context.WithCancel(context.Background())
+	s.stop = stop
+
+	for _, j := range s.jobs {
+		s.wg.Add(1)
+		go func(j job) {
+			defer s.wg.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+
+	if len(s.jobs) > 0 {
+		log.Printf("Job scheduler started with %d jobs", len(s.jobs))
+	}
+}
+
+// Shutdown cancels running jobs and waits for them to return, or for ctx to expire
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.stop == nil {
+		return nil
+	}
+
+	var err error
+	s.stopOnce.Do(func() {
+		s.stop()
+
+		done := make(chan struct{})
+		go func() {
+			s.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			err = fmt.Errorf("jobs did not stop in time: %w", ctx.Err())
+		}
+	})
+	return err
+}
+
+// loop runs one job until ctx is cancelled
+func (s *Scheduler) loop(ctx context.Context, j job) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		s.run(ctx, j)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run executes a single run of a job, bounded by its interval
+func (s *Scheduler) run(parent context.Context, j job) {
+	ctx, cancel := context.WithTimeout(parent, j.interval)
+	defer cancel()
+
+	start := time.Now()
+	if err := j.fn(ctx); err != nil {
+		// Errors caused by shutdown are expected
+		if parent.Err() == nil {
+			log.Printf("Job %s failed: %v", j.name, err)
+		}
+		return
+	}
+	log.Printf("Job %s finished in %s", j.name, time.Since(start).Round(time.Millisecond))
+}
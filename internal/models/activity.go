@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ActivityType is the kind of event in a user's activity timeline
+type ActivityType string
+
+const (
+	ActivitySubmitted          ActivityType = "submitted"
+	ActivityScored             ActivityType = "scored"
+	ActivityStarterPackStarted ActivityType = "starter_pack_started"
+)
+
+// ActivityDay is one cell of the activity calendar
+type ActivityDay struct {
+	Date        string `json:"date"` // Local date in the user's time zone, YYYY-MM-DD
+	Submissions int    `json:"submissions"`
+	Reviews     int    `json:"reviews"`
+}
+
+// ActivityEvent is one entry of the activity timeline
+type ActivityEvent struct {
+	Type           ActivityType `json:"type"`
+	OccurredAt     time.Time    `json:"occurred_at"`
+	SubmissionID   string       `json:"submission_id,omitempty"`
+	ChallengeID    string       `json:"challenge_id,omitempty"`
+	ChallengeTitle string       `json:"challenge_title,omitempty"`
+	Score          *int         `json:"score,omitempty"` // AI review score (0-100), scored events only
+	StarterPackID  string       `json:"starter_pack_id,omitempty"`
+}
+
+// UserActivity is a user's activity calendar and a page of their timeline
+// between two local dates
+type UserActivity struct {
+	Username         string          `json:"username"`
+	Timezone         string          `json:"timezone"`
+	From             string          `json:"from"`
+	To               string          `json:"to"`
+	TotalSubmissions int             `json:"total_submissions"`
+	TotalReviews     int             `json:"total_reviews"`
+	Days             []ActivityDay   `json:"days"` // Every date in the range, oldest first
+	Timeline         []ActivityEvent `json:"timeline"`
+	NextCursor       string          `json:"next_cursor,omitempty"`
+}
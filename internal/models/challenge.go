@@ -15,6 +15,15 @@ const (
 	DifficultyHard   Difficulty = "Hard"
 )
 
+// IsValid reports whether d is one of the known difficulty levels
+func (d Difficulty) IsValid() bool {
+	switch d {
+	case DifficultyEasy, DifficultyMedium, DifficultyHard:
+		return true
+	}
+	return false
+}
+
 // ChallengeType represents the type of challenge
 type ChallengeType string
 
@@ -25,21 +34,31 @@ const (
 	ChallengeTypeBugfix   ChallengeType = "bugfix"   // Fix bugs in codebase
 )
 
+// IsValid reports whether t is one of the known challenge types
+func (t ChallengeType) IsValid() bool {
+	switch t {
+	case ChallengeTypeProject, ChallengeTypeFeature, ChallengeTypeRefactor, ChallengeTypeBugfix:
+		return true
+	}
+	return false
+}
+
 // Challenge represents a DevArena coding challenge
 type Challenge struct {
-	ID              string        `json:"id" gorm:"primaryKey;type:varchar(255)"`
-	Title           string        `json:"title" gorm:"type:varchar(255);not null"`
-	Description     string        `json:"description" gorm:"type:text;not null"`
-	Difficulty      Difficulty    `json:"difficulty" gorm:"type:varchar(20);not null"`
-	Type            ChallengeType `json:"type" gorm:"type:varchar(50);default:project"`
-	MaxScore        int           `json:"max_score" gorm:"not null;default:100"`
-	RepoTemplateURL string        `json:"repo_template_url" gorm:"type:text"` // Starter template repo
-	Requirements    Requirements  `json:"requirements" gorm:"type:jsonb"`     // Review criteria
-	TechStack       TechStack     `json:"tech_stack" gorm:"type:jsonb"`       // Expected technologies
-	EstimatedHours  int           `json:"estimated_hours" gorm:"default:4"`   // Estimated completion time
-	IsPublished     bool          `json:"is_published" gorm:"default:false"`
-	CreatedAt       time.Time     `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt       time.Time     `json:"updated_at" gorm:"autoUpdateTime"`
+	ID              string          `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	Title           string          `json:"title" gorm:"type:varchar(255);not null"`
+	Description     string          `json:"description" gorm:"type:text;not null"`
+	Difficulty      Difficulty      `json:"difficulty" gorm:"type:varchar(20);not null"`
+	Type            ChallengeType   `json:"type" gorm:"type:varchar(50);default:project"`
+	MaxScore        int             `json:"max_score" gorm:"not null;default:100"`
+	RepoTemplateURL string          `json:"repo_template_url" gorm:"type:text"`           // Starter template repo
+	Requirements    Requirements    `json:"requirements" gorm:"type:jsonb"`               // Review criteria
+	TechStack       TechStack       `json:"tech_stack" gorm:"type:jsonb"`                 // Expected technologies
+	EstimatedHours  int             `json:"estimated_hours" gorm:"default:4"`             // Estimated completion time
+	CategoryWeights CategoryWeights `json:"category_weights,omitempty" gorm:"type:jsonb"` // Review category weights for scoring
+	IsPublished     bool            `json:"is_published" gorm:"default:false"`
+	CreatedAt       time.Time       `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time       `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Computed fields (not stored, calculated at query time)
 	SuccessRate     float64 `json:"success_rate" gorm:"-"`
@@ -50,6 +69,23 @@ type Challenge struct {
 	Submissions []Submission `json:"submissions,omitempty" gorm:"foreignKey:ChallengeID"`
 }
 
+// ChallengeRequest is the payload for authoring a challenge. Creating requires
+// title, description and difficulty; an update only changes the fields that are
+// present. TagIDs replaces the challenge's tags.
+type ChallengeRequest struct {
+	Title           *string          `json:"title"`
+	Description     *string          `json:"description"`
+	Difficulty      *Difficulty      `json:"difficulty"`
+	Type            *ChallengeType   `json:"type"`
+	MaxScore        *int             `json:"max_score"`
+	RepoTemplateURL *string          `json:"repo_template_url"`
+	Requirements    *Requirements    `json:"requirements"`
+	TechStack       *TechStack       `json:"tech_stack"`
+	EstimatedHours  *int             `json:"estimated_hours"`
+	CategoryWeights *CategoryWeights `json:"category_weights"` // An empty object restores the default weights
+	TagIDs          *[]string        `json:"tag_ids"`
+}
+
 // Requirements represents the review criteria for a challenge
 type Requirements []string
 
@@ -88,6 +124,28 @@ func (t *TechStack) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, t)
 }
 
+// CategoryWeights maps review category names to their relative weight in the score
+type CategoryWeights map[string]float64
+
+func (w CategoryWeights) Value() (driver.Value, error) {
+	if w == nil {
+		return nil, nil
+	}
+	return json.Marshal(w)
+}
+
+func (w *CategoryWeights) Scan(value interface{}) error {
+	if value == nil {
+		*w = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(bytes, w)
+}
+
 // ChallengeResponse is the API response for challenges (with computed fields)
 type ChallengeResponse struct {
 	ID              string        `json:"id"`
@@ -102,3 +160,12 @@ type ChallengeResponse struct {
 	Tags            []string      `json:"tags"`
 	IsSolved        bool          `json:"is_solved"`
 }
+
+// ChallengeDetailResponse is the API response for a single challenge, including
+// the caller's own attempts when they are signed in
+type ChallengeDetailResponse struct {
+	Challenge
+	MySubmissions []SubmissionResponse `json:"my_submissions"`
+	BestScore     *int                 `json:"best_score"`
+	IsSolved      bool                 `json:"is_solved"`
+}
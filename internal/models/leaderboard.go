@@ -15,6 +15,7 @@ type LeaderboardEntry struct {
 	AverageReviewScore  float64   `json:"average_review_score"`
 	CurrentStreak       int       `json:"current_streak"`
 	LastActivityAt      time.Time `json:"last_activity_at"`
+	RankChange          *int      `json:"rank_change,omitempty"` // Places gained (+) or lost (-) since yesterday
 }
 
 // LeaderboardFilter defines filtering options for leaderboard queries
@@ -25,3 +26,27 @@ type LeaderboardFilter struct {
 	Limit      int    `json:"limit"`
 	Offset     int    `json:"offset"`
 }
+
+// Leaderboard periods
+const (
+	PeriodAllTime = "all_time"
+	PeriodWeekly  = "weekly"
+	PeriodMonthly = "monthly"
+)
+
+// LeaderboardResponse is the API response for a page of the leaderboard
+type LeaderboardResponse struct {
+	Entries     []LeaderboardEntry `json:"entries"`
+	Total       int                `json:"total"`
+	Filter      LeaderboardFilter  `json:"filter"`
+	RefreshedAt *time.Time         `json:"refreshed_at,omitempty"` // Set when served from a snapshot
+}
+
+// LeaderboardPosition is the caller's own leaderboard entry with the users ranked around them
+type LeaderboardPosition struct {
+	Entry       *LeaderboardEntry  `json:"entry"` // nil when the caller has no reviewed submissions in the window
+	Neighbors   []LeaderboardEntry `json:"neighbors"`
+	Total       int                `json:"total"`
+	Filter      LeaderboardFilter  `json:"filter"`
+	RefreshedAt *time.Time         `json:"refreshed_at,omitempty"`
+}
@@ -0,0 +1,56 @@
+package models
+
+// Experience levels accepted during onboarding
+const (
+	ExperienceBeginner     = "beginner"
+	ExperienceIntermediate = "intermediate"
+	ExperienceAdvanced     = "advanced"
+	ExperienceExpert       = "expert"
+)
+
+// Learning paths accepted during onboarding
+const (
+	PathFrontend  = "frontend"
+	PathBackend   = "backend"
+	PathFullstack = "fullstack"
+	PathAI        = "ai"
+	PathMobile    = "mobile"
+)
+
+// OnboardingOption is one choice the frontend can offer for an onboarding question
+type OnboardingOption struct {
+	Value    string `json:"value"`
+	Label    string `json:"label"`
+	Category string `json:"category,omitempty"` // Tag category, for technologies
+}
+
+// ExperienceOptions lists the valid experience levels, least experienced first
+var ExperienceOptions = []OnboardingOption{
+	{Value: ExperienceBeginner, Label: "Beginner"},
+	{Value: ExperienceIntermediate, Label: "Intermediate"},
+	{Value: ExperienceAdvanced, Label: "Advanced"},
+	{Value: ExperienceExpert, Label: "Expert"},
+}
+
+// PathOptions lists the valid learning paths
+var PathOptions = []OnboardingOption{
+	{Value: PathFrontend, Label: "Frontend"},
+	{Value: PathBackend, Label: "Backend"},
+	{Value: PathFullstack, Label: "Full Stack"},
+	{Value: PathAI, Label: "AI / ML"},
+	{Value: PathMobile, Label: "Mobile"},
+}
+
+// OnboardingOptions is the vocabulary onboarding answers are validated against.
+// Technologies are the slugs of the challenge tags.
+type OnboardingOptions struct {
+	Experience   []OnboardingOption `json:"experience"`
+	Paths        []OnboardingOption `json:"paths"`
+	Technologies []OnboardingOption `json:"technologies"`
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field"` // e.g. "experience" or "paths[1]"
+	Message string `json:"message"`
+}
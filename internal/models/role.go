@@ -0,0 +1,24 @@
+package models
+
+// Role is what a user is allowed to do beyond using the platform
+type Role string
+
+const (
+	RoleUser     Role = "user"     // Solves challenges
+	RoleReviewer Role = "reviewer" // Moderates submissions and reviews
+	RoleAdmin    Role = "admin"    // Manages content and users
+)
+
+// IsValid reports whether r is one of the known roles
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleUser, RoleReviewer, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// UpdateRoleRequest is the payload for changing a user's role
+type UpdateRoleRequest struct {
+	Role Role `json:"role" binding:"required"`
+}
@@ -9,14 +9,15 @@ import (
 // StarterPack represents a personalized learning pack generated from onboarding
 type StarterPack struct {
 	ID              string      `json:"id" gorm:"primaryKey;type:varchar(255)"`
-	UserID          string      `json:"user_id" gorm:"uniqueIndex;type:varchar(255);not null"`
+	UserID          string      `json:"user_id" gorm:"index;type:varchar(255);not null"`
 	Experience      string      `json:"experience" gorm:"type:varchar(50)"` // beginner, intermediate, advanced, expert
 	Paths           StringArray `json:"paths" gorm:"type:jsonb"`            // frontend, backend, fullstack, ai, mobile
 	Technologies    StringArray `json:"technologies" gorm:"type:jsonb"`     // Selected technologies
 	Challenges      StringArray `json:"challenge_ids" gorm:"type:jsonb"`    // Recommended challenge IDs
 	CurrentProgress int         `json:"current_progress" gorm:"default:0"`  // Completed challenges count
 	TotalChallenges int         `json:"total_challenges" gorm:"default:0"`  // Total challenges in pack
-	IsActive        bool        `json:"is_active" gorm:"default:true"`
+	IsActive        bool        `json:"is_active" gorm:"default:true"`      // At most one active pack per user
+	ArchivedAt      *time.Time  `json:"archived_at,omitempty"`
 	CreatedAt       time.Time   `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time   `json:"updated_at" gorm:"autoUpdateTime"`
 
@@ -49,6 +50,9 @@ type StarterPackResponse struct {
 	CurrentProgress int                     `json:"current_progress"`
 	TotalChallenges int                     `json:"total_challenges"`
 	ProgressPercent float64                 `json:"progress_percent"`
+	IsActive        bool                    `json:"is_active"`
+	CreatedAt       time.Time               `json:"created_at"`
+	ArchivedAt      *time.Time              `json:"archived_at,omitempty"`
 }
 
 // ChallengeWithProgress represents a challenge with user progress
@@ -59,6 +63,7 @@ type ChallengeWithProgress struct {
 	TechStack   []string   `json:"tech_stack"`
 	OrderIndex  int        `json:"order_index"`
 	IsCompleted bool       `json:"is_completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
 	Score       int        `json:"score,omitempty"`
 }
 
@@ -14,16 +14,18 @@ const (
 
 // Submission represents a user's GitHub repository submission for a challenge
 type Submission struct {
-	ID          string           `json:"id" gorm:"primaryKey;type:varchar(255)"`
-	UserID      string           `json:"user_id" gorm:"type:varchar(255);not null;index"`
-	ChallengeID string           `json:"challenge_id" gorm:"type:varchar(255);not null;index"`
-	RepoURL     string           `json:"repo_url" gorm:"type:text;not null"`
-	Branch      string           `json:"branch" gorm:"type:varchar(100);default:main"`
-	CommitHash  string           `json:"commit_hash" gorm:"type:varchar(64)"`
-	Status      SubmissionStatus `json:"status" gorm:"type:varchar(50);not null;default:pending"`
-	Score       int              `json:"score" gorm:"default:0;comment:Overall score 0-100"`
-	CreatedAt   time.Time        `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+	ID            string           `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	UserID        string           `json:"user_id" gorm:"type:varchar(255);not null;index"`
+	ChallengeID   string           `json:"challenge_id" gorm:"type:varchar(255);not null;index"`
+	RepoURL       string           `json:"repo_url" gorm:"type:text;not null"`
+	Branch        string           `json:"branch" gorm:"type:varchar(100);default:main"`
+	CommitHash    string           `json:"commit_hash" gorm:"type:varchar(64)"`
+	Status        SubmissionStatus `json:"status" gorm:"type:varchar(50);not null;default:pending"`
+	Score         int              `json:"score" gorm:"default:0;comment:Weighted review score scaled to the challenge max score"`
+	Attempts      int              `json:"attempts" gorm:"default:0"`
+	FailureReason string           `json:"failure_reason,omitempty" gorm:"type:text"` // Why the review failed
+	CreatedAt     time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	User      User       `json:"user,omitempty" gorm:"foreignKey:UserID"`
@@ -36,4 +38,19 @@ type SubmissionRequest struct {
 	ChallengeID string `json:"challenge_id" binding:"required"`
 	RepoURL     string `json:"repo_url" binding:"required,url"`
 	Branch      string `json:"branch"`
+	CommitHash  string `json:"commit_hash"` // Optional, pins the review to a specific commit
+}
+
+// SubmissionResponse is the API response for a submission
+type SubmissionResponse struct {
+	ID            string           `json:"id"`
+	ChallengeID   string           `json:"challenge_id"`
+	RepoURL       string           `json:"repo_url"`
+	Branch        string           `json:"branch"`
+	CommitHash    string           `json:"commit_hash"`
+	Status        SubmissionStatus `json:"status"`
+	Score         int              `json:"score"`
+	FailureReason string           `json:"failure_reason,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
 }
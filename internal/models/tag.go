@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode"
+)
 
 // Tag represents a challenge category/tag
 type Tag struct {
@@ -21,3 +25,58 @@ const (
 	TagCategoryTopic    = "Topic"
 	TagCategorySkill    = "Skill"
 )
+
+// TagWithCount is a tag with the number of published challenges using it
+type TagWithCount struct {
+	Tag
+	ChallengeCount int `json:"challenge_count"`
+}
+
+// TagCategoryGroup is the tags of one category
+type TagCategoryGroup struct {
+	Category string         `json:"category"`
+	Tags     []TagWithCount `json:"tags"`
+}
+
+// TagRequest is the payload for creating a tag, or editing one when sent as a
+// partial update. An empty slug on create is generated from the name.
+type TagRequest struct {
+	Name     *string `json:"name"`
+	Slug     *string `json:"slug"`
+	Category *string `json:"category"`
+	Color    *string `json:"color"`
+}
+
+// MergeTagsRequest lists the tags to fold into another tag
+type MergeTagsRequest struct {
+	SourceIDs []string `json:"source_ids" binding:"required"`
+}
+
+// slugWords spells out symbols that carry meaning in technology names
+var slugWords = map[rune]string{'+': "plus", '#': "sharp"}
+
+// Slugify turns a tag name into a URL-safe slug, e.g. "Node.js" -> "nodejs",
+// "System Design" -> "system-design" and "C++" -> "cplusplus"
+func Slugify(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		part := slugWords[r]
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			part = string(r)
+		}
+		switch {
+		case part != "":
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteString(part)
+		case r == '.' || r == '\'':
+			// Dropped so "Node.js" becomes "nodejs"
+		default:
+			dash = true
+		}
+	}
+	return b.String()
+}
@@ -4,35 +4,85 @@ import "time"
 
 // User represents a DevArena user
 type User struct {
-	ID                  string    `json:"id" gorm:"primaryKey;type:varchar(255)"`
-	ClerkUserID         string    `json:"clerk_user_id" gorm:"uniqueIndex;type:varchar(255);not null"`
-	Email               string    `json:"email" gorm:"uniqueIndex;type:varchar(255);not null"`
-	Username            string    `json:"username" gorm:"uniqueIndex;type:varchar(100)"`
-	DisplayName         string    `json:"display_name" gorm:"type:varchar(255)"`
-	AvatarURL           string    `json:"avatar_url" gorm:"type:text"`
-	Bio                 string    `json:"bio" gorm:"type:text"`
-	GitHubUsername      string    `json:"github_username" gorm:"type:varchar(100);index"`
-	GitHubConnected     bool      `json:"github_connected" gorm:"default:false"`
-	OnboardingCompleted bool      `json:"onboarding_completed" gorm:"default:false"`
-	CurrentStreak       int       `json:"current_streak" gorm:"default:0"`
-	LongestStreak       int       `json:"longest_streak" gorm:"default:0"`
-	TotalScore          int       `json:"total_score" gorm:"default:0"`
-	Rank                int       `json:"rank" gorm:"default:0"`
-	ChallengesCompleted int       `json:"challenges_completed" gorm:"default:0"`
-	CreatedAt           time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt           time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                  string     `json:"id" gorm:"primaryKey;type:varchar(255)"`
+	ClerkUserID         string     `json:"clerk_user_id" gorm:"uniqueIndex;type:varchar(255);not null"`
+	Email               string     `json:"email" gorm:"uniqueIndex;type:varchar(255)"` // Empty until synced from Clerk for just-in-time users
+	Username            string     `json:"username" gorm:"uniqueIndex;type:varchar(100)"`
+	DisplayName         string     `json:"display_name" gorm:"type:varchar(255)"`
+	AvatarURL           string     `json:"avatar_url" gorm:"type:text"`
+	Bio                 string     `json:"bio" gorm:"type:text"`
+	GitHubUsername      string     `json:"github_username" gorm:"type:varchar(100);index"`
+	GitHubConnected     bool       `json:"github_connected" gorm:"default:false"`
+	OnboardingCompleted bool       `json:"onboarding_completed" gorm:"default:false"`
+	CurrentStreak       int        `json:"current_streak" gorm:"default:0"`
+	LongestStreak       int        `json:"longest_streak" gorm:"default:0"`
+	LastActiveDate      *time.Time `json:"last_active_date,omitempty" gorm:"type:date"`  // Local date of the latest active day
+	Timezone            string     `json:"timezone" gorm:"type:varchar(64);default:UTC"` // IANA time zone used for streak days
+	Role                Role       `json:"role" gorm:"type:varchar(20);default:user"`
+	TotalScore          int        `json:"total_score" gorm:"default:0"`
+	Rank                int        `json:"rank" gorm:"default:0"`
+	ChallengesCompleted int        `json:"challenges_completed" gorm:"default:0"`
+	CreatedAt           time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 
 	// Relationships
 	Submissions  []Submission  `json:"submissions,omitempty" gorm:"foreignKey:UserID"`
 	StarterPacks []StarterPack `json:"starter_packs,omitempty" gorm:"foreignKey:UserID"`
 }
 
+// UpdateProfileRequest is the body of PATCH /me. Omitted fields are left
+// unchanged; an empty string clears an optional field. The username can be
+// changed but not cleared.
+type UpdateProfileRequest struct {
+	Username       *string `json:"username"`
+	DisplayName    *string `json:"display_name"`
+	Bio            *string `json:"bio"`
+	GitHubUsername *string `json:"github_username"`
+	Timezone       *string `json:"timezone"`
+}
+
 // UserStats represents aggregated user statistics
 type UserStats struct {
 	UserID              string  `json:"user_id"`
 	ChallengesCompleted int     `json:"challenges_completed"`
-	AverageScore        float64 `json:"average_score"`
+	AverageScore        float64 `json:"average_score"` // Mean AI review score (0-100) over all reviews
 	CurrentStreak       int     `json:"current_streak"`
 	TotalReviews        int     `json:"total_reviews"`
-	TopPercentile       float64 `json:"top_percentile"`
+	TopPercentile       float64 `json:"top_percentile"` // e.g. 5 for the top 5% by total score, 0 when unranked
+}
+
+// DifficultyStats summarizes a user's progress on challenges of one difficulty
+type DifficultyStats struct {
+	Difficulty Difficulty `json:"difficulty"`
+	Attempted  int        `json:"attempted"` // Challenges with at least one submission
+	Completed  int        `json:"completed"` // Challenges with a reviewed submission
+	Available  int        `json:"available"` // Published challenges of this difficulty
+}
+
+// PublicSubmission is a reviewed submission as shown on a public profile
+type PublicSubmission struct {
+	ID             string     `json:"id"`
+	ChallengeID    string     `json:"challenge_id"`
+	ChallengeTitle string     `json:"challenge_title"`
+	Difficulty     Difficulty `json:"difficulty"`
+	RepoURL        string     `json:"repo_url"`
+	Score          int        `json:"score"`
+	MaxScore       int        `json:"max_score"`
+	ReviewedAt     time.Time  `json:"reviewed_at"`
+}
+
+// PublicProfile is the community-facing view of a user
+type PublicProfile struct {
+	Username            string             `json:"username"`
+	DisplayName         string             `json:"display_name"`
+	AvatarURL           string             `json:"avatar_url"`
+	Bio                 string             `json:"bio"`
+	GitHubUsername      string             `json:"github_username"`
+	TotalScore          int                `json:"total_score"`
+	Rank                int                `json:"rank"`
+	LongestStreak       int                `json:"longest_streak"`
+	JoinedAt            time.Time          `json:"joined_at"`
+	Stats               UserStats          `json:"stats"`
+	DifficultyBreakdown []DifficultyStats  `json:"difficulty_breakdown"`
+	RecentSubmissions   []PublicSubmission `json:"recent_submissions"`
 }
@@ -0,0 +1,543 @@
+package review
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+const (
+	maxScannedFiles    = 2000
+	maxScannedFileSize = 256 * 1024
+	longLineLength     = 120
+)
+
+// Directories that are never part of the submitted source
+var skippedDirs = map[string]bool{
+	".git": true, "node_modules": true, "vendor": true, "dist": true, "build": true,
+	"__pycache__": true, ".venv": true, "venv": true, ".next": true, "target": true,
+}
+
+var sourceExtensions = map[string]bool{
+	".go": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".py": true,
+	".java": true, ".rb": true, ".rs": true, ".c": true, ".cpp": true, ".h": true,
+	".cs": true, ".php": true, ".kt": true, ".swift": true, ".css": true, ".scss": true,
+	".html": true, ".vue": true, ".svelte": true, ".sql": true,
+}
+
+var manifestFiles = map[string]bool{
+	"go.mod": true, "package.json": true, "requirements.txt": true, "pyproject.toml": true,
+	"Cargo.toml": true, "pom.xml": true, "build.gradle": true, "Gemfile": true, "composer.json": true,
+}
+
+var lockFiles = map[string]bool{
+	"go.sum": true, "package-lock.json": true, "yarn.lock": true, "pnpm-lock.yaml": true,
+	"poetry.lock": true, "Cargo.lock": true, "Gemfile.lock": true, "composer.lock": true,
+}
+
+var lintConfigPrefixes = []string{
+	".eslintrc", ".prettierrc", ".golangci", ".editorconfig", ".flake8", ".pylintrc",
+	"eslint.config", "prettier.config", "ruff.toml", ".rubocop",
+}
+
+var (
+	testFilePattern     = regexp.MustCompile(`(_test\.go|\.test\.[jt]sx?|\.spec\.[jt]sx?|^test_.*\.py|_test\.py|Test\.java|_spec\.rb)$`)
+	privateKeyPattern   = regexp.MustCompile(`-----BEGIN (RSA |EC |OPENSSH |DSA )?PRIVATE KEY-----`)
+	hardcodedSecretExpr = regexp.MustCompile(`(?i)(password|passwd|secret|api[_-]?key|access[_-]?token)\s*[:=]\s*["'][^"'\s]{6,}["']`)
+	todoPattern         = regexp.MustCompile(`\b(TODO|FIXME|XXX|HACK)\b`)
+)
+
+var requirementStopwords = map[string]bool{
+	"with": true, "that": true, "must": true, "should": true, "from": true, "into": true,
+	"using": true, "only": true, "have": true, "each": true, "when": true, "your": true,
+	"this": true, "they": true, "them": true, "their": true, "will": true, "able": true,
+	"make": true, "sure": true, "also": true, "more": true, "than": true, "implement": true,
+	"add": true, "use": true, "all": true, "and": true, "the": true, "for": true,
+}
+
+// HeuristicReviewer is a deterministic, rule-based reviewer that works offline.
+// It scores a checked-out repository with simple static signals so the full review
+// flow can be exercised without an external LLM. The same tree always yields the
+// same review.
+type HeuristicReviewer struct{}
+
+// NewHeuristicReviewer creates a heuristic reviewer
+func NewHeuristicReviewer() *HeuristicReviewer {
+	return &HeuristicReviewer{}
+}
+
+// repoStats holds the signals collected from a repository walk
+type repoStats struct {
+	files           int
+	sourceFiles     int
+	testFiles       int
+	sourceDirs      map[string]bool
+	totalLines      int
+	longLines       int
+	commentLines    int
+	todos           int
+	largestFile     int
+	readmeSize      int
+	hasGitignore    bool
+	hasManifest     bool
+	hasLockFile     bool
+	hasLintConfig   bool
+	hasEnvFile      bool
+	hasPrivateKey   bool
+	hardcodedSecret int
+	committedDeps   bool
+	binaryFiles     int
+	corpus          strings.Builder
+}
+
+// Review scores the submission's source tree
+func (r *HeuristicReviewer) Review(ctx context.Context, in Input) (*models.AIReview, error) {
+	if in.SourceDir == "" {
+		return r.reviewWithoutSource(in), nil
+	}
+
+	stats, err := collectStats(ctx, in.SourceDir)
+	if err != nil {
+		return nil, err
+	}
+
+	categories := models.ReviewCategories{
+		scoreCodeQuality(stats),
+		scoreBestPractices(stats),
+		scoreArchitecture(stats),
+		scoreDocumentation(stats),
+		scoreTestCoverage(stats),
+		scoreSecurity(stats),
+		scorePerformance(stats),
+		scoreMaintainability(stats),
+	}
+
+	met, unmet := matchRequirements(in.Requirements, strings.ToLower(stats.corpus.String()))
+
+	total := 0
+	for _, cat := range categories {
+		total += cat.Score
+	}
+	categoryAverage := float64(total) / float64(len(categories))
+
+	overall := categoryAverage
+	if len(in.Requirements) > 0 {
+		coverage := 100 * float64(len(met)) / float64(len(in.Requirements))
+		overall = 0.7*categoryAverage + 0.3*coverage
+	}
+
+	suggestions := models.Suggestions{}
+	for _, req := range unmet {
+		suggestions = append(suggestions, fmt.Sprintf("Requirement may not be addressed: %s", req))
+	}
+	for _, cat := range categories {
+		if cat.Score < 60 {
+			suggestions = append(suggestions, fmt.Sprintf("Improve %s: %s", strings.ToLower(cat.Name), cat.Notes))
+		}
+	}
+
+	feedback := fmt.Sprintf(
+		"Analyzed %d files (%d source, %d test). %d of %d requirements appear to be addressed.",
+		stats.files, stats.sourceFiles, stats.testFiles, len(met), len(in.Requirements),
+	)
+
+	return &models.AIReview{
+		SubmissionID: in.Submission.ID,
+		OverallScore: clampScore(int(math.Round(overall))),
+		Categories:   categories,
+		Feedback:     feedback,
+		Suggestions:  suggestions,
+	}, nil
+}
+
+// reviewWithoutSource returns a neutral review when no code could be inspected
+func (r *HeuristicReviewer) reviewWithoutSource(in Input) *models.AIReview {
+	names := []string{
+		models.CategoryCodeQuality, models.CategoryBestPractices, models.CategoryArchitecture,
+		models.CategoryDocumentation, models.CategoryTestCoverage, models.CategorySecurity,
+		models.CategoryPerformance, models.CategoryMaintainability,
+	}
+
+	categories := make(models.ReviewCategories, 0, len(names))
+	for _, name := range names {
+		categories = append(categories, models.ReviewCategory{
+			Name:  name,
+			Score: 50,
+			Notes: "Source code was not available for analysis",
+		})
+	}
+
+	return &models.AIReview{
+		SubmissionID: in.Submission.ID,
+		OverallScore: 50,
+		Categories:   categories,
+		Feedback:     "The repository could not be analyzed, so a neutral score was assigned.",
+		Suggestions:  models.Suggestions{},
+	}
+}
+
+// collectStats walks the source tree in lexical order and gathers signals
+func collectStats(ctx context.Context, root string) (*repoStats, error) {
+	stats := &repoStats{sourceDirs: map[string]bool{}}
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && skippedDirs[name] {
+				if name == "node_modules" {
+					stats.committedDeps = true
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		stats.files++
+		if stats.files > maxScannedFiles {
+			return filepath.SkipAll
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+
+		switch {
+		case strings.HasPrefix(strings.ToLower(name), "readme"):
+			stats.readmeSize = max(stats.readmeSize, int(info.Size()))
+		case name == ".gitignore":
+			stats.hasGitignore = true
+		case name == ".env" || strings.HasPrefix(name, ".env.") && !strings.HasSuffix(name, ".example"):
+			stats.hasEnvFile = true
+		}
+		if manifestFiles[name] {
+			stats.hasManifest = true
+		}
+		if lockFiles[name] {
+			stats.hasLockFile = true
+		}
+		for _, prefix := range lintConfigPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				stats.hasLintConfig = true
+			}
+		}
+
+		if info.Size() > maxScannedFileSize {
+			stats.binaryFiles++
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(name))
+		isSource := sourceExtensions[ext]
+		if isSource {
+			stats.sourceFiles++
+			stats.sourceDirs[filepath.Dir(rel)] = true
+			if testFilePattern.MatchString(name) {
+				stats.testFiles++
+			}
+		}
+
+		return scanFile(path, isSource, stats)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze repository: %w", err)
+	}
+
+	return stats, nil
+}
+
+// scanFile reads one file, updating line-based metrics for source files and the
+// search corpus used for requirement matching
+func scanFile(path string, isSource bool, stats *repoStats) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if !isText(data) {
+		stats.binaryFiles++
+		return nil
+	}
+
+	content := string(data)
+	if privateKeyPattern.MatchString(content) {
+		stats.hasPrivateKey = true
+	}
+	stats.hardcodedSecret += len(hardcodedSecretExpr.FindAllStringIndex(content, -1))
+
+	if stats.corpus.Len() < 4*1024*1024 {
+		stats.corpus.WriteString(content)
+		stats.corpus.WriteByte('\n')
+	}
+
+	if !isSource {
+		return nil
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxScannedFileSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		lines++
+		if len(line) > longLineLength {
+			stats.longLines++
+		}
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(trimmed, "/*") || strings.HasPrefix(trimmed, "*") ||
+			strings.HasPrefix(trimmed, "\"\"\"") || strings.HasPrefix(trimmed, "<!--") {
+			stats.commentLines++
+		}
+		if todoPattern.MatchString(line) {
+			stats.todos++
+		}
+	}
+
+	stats.totalLines += lines
+	stats.largestFile = max(stats.largestFile, lines)
+	return nil
+}
+
+// isText reports whether data looks like text (no NUL bytes in the first 8KB)
+func isText(data []byte) bool {
+	n := min(len(data), 8*1024)
+	for _, b := range data[:n] {
+		if b == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func scoreCodeQuality(s *repoStats) models.ReviewCategory {
+	if s.totalLines == 0 {
+		return models.ReviewCategory{Name: models.CategoryCodeQuality, Score: 0, Notes: "No source code found"}
+	}
+	longRatio := float64(s.longLines) / float64(s.totalLines)
+	score := 100 - int(math.Round(longRatio*200)) - min(20, 2*s.todos)
+	return models.ReviewCategory{
+		Name:  models.CategoryCodeQuality,
+		Score: clampScore(score),
+		Notes: fmt.Sprintf("%.1f%% of lines exceed %d characters; %d TODO/FIXME markers", longRatio*100, longLineLength, s.todos),
+	}
+}
+
+func scoreBestPractices(s *repoStats) models.ReviewCategory {
+	score := 0
+	var missing []string
+	if s.hasGitignore {
+		score += 30
+	} else {
+		missing = append(missing, "a .gitignore")
+	}
+	if s.hasManifest {
+		score += 30
+	} else {
+		missing = append(missing, "a dependency manifest")
+	}
+	if s.hasLockFile {
+		score += 20
+	} else {
+		missing = append(missing, "a dependency lock file")
+	}
+	if s.hasLintConfig {
+		score += 20
+	} else {
+		missing = append(missing, "linter/formatter configuration")
+	}
+
+	notes := "Project follows common repository conventions"
+	if len(missing) > 0 {
+		notes = "Missing " + strings.Join(missing, ", ")
+	}
+	return models.ReviewCategory{Name: models.CategoryBestPractices, Score: score, Notes: notes}
+}
+
+func scoreArchitecture(s *repoStats) models.ReviewCategory {
+	dirs := len(s.sourceDirs)
+	if s.sourceFiles == 0 {
+		return models.ReviewCategory{Name: models.CategoryArchitecture, Score: 0, Notes: "No source code found"}
+	}
+	score := 40 + 15*min(dirs, 4)
+	return models.ReviewCategory{
+		Name:  models.CategoryArchitecture,
+		Score: clampScore(score),
+		Notes: fmt.Sprintf("Source is organized across %d directories", dirs),
+	}
+}
+
+func scoreDocumentation(s *repoStats) models.ReviewCategory {
+	score := 0
+	var notes []string
+	if s.readmeSize > 0 {
+		score += 50
+		if s.readmeSize >= 500 {
+			score += 20
+		} else {
+			notes = append(notes, "README is very short")
+		}
+	} else {
+		notes = append(notes, "no README found")
+	}
+
+	commentRatio := 0.0
+	if s.totalLines > 0 {
+		commentRatio = float64(s.commentLines) / float64(s.totalLines)
+	}
+	// 10% comment lines earns the full 30 points
+	score += int(math.Round(math.Min(commentRatio/0.10, 1) * 30))
+	notes = append(notes, fmt.Sprintf("%.1f%% of source lines are comments", commentRatio*100))
+
+	return models.ReviewCategory{
+		Name:  models.CategoryDocumentation,
+		Score: clampScore(score),
+		Notes: capitalize(strings.Join(notes, "; ")),
+	}
+}
+
+func scoreTestCoverage(s *repoStats) models.ReviewCategory {
+	if s.testFiles == 0 {
+		return models.ReviewCategory{Name: models.CategoryTestCoverage, Score: 0, Notes: "No test files found"}
+	}
+	ratio := float64(s.testFiles) / float64(max(s.sourceFiles-s.testFiles, 1))
+	// One test file for every two or three source files earns the full score
+	score := int(math.Round(math.Min(ratio/0.4, 1) * 100))
+	return models.ReviewCategory{
+		Name:  models.CategoryTestCoverage,
+		Score: clampScore(score),
+		Notes: fmt.Sprintf("%d test files for %d source files", s.testFiles, s.sourceFiles-s.testFiles),
+	}
+}
+
+func scoreSecurity(s *repoStats) models.ReviewCategory {
+	score := 100
+	var issues []string
+	if s.hasPrivateKey {
+		score -= 40
+		issues = append(issues, "a private key is committed")
+	}
+	if s.hasEnvFile {
+		score -= 30
+		issues = append(issues, "an .env file is committed")
+	}
+	if s.hardcodedSecret > 0 {
+		score -= min(30, 10*s.hardcodedSecret)
+		issues = append(issues, fmt.Sprintf("%d possible hardcoded secrets", s.hardcodedSecret))
+	}
+
+	notes := "No committed secrets detected"
+	if len(issues) > 0 {
+		notes = capitalize(strings.Join(issues, "; "))
+	}
+	return models.ReviewCategory{Name: models.CategorySecurity, Score: clampScore(score), Notes: notes}
+}
+
+func scorePerformance(s *repoStats) models.ReviewCategory {
+	score := 90
+	var issues []string
+	if s.committedDeps {
+		score -= 30
+		issues = append(issues, "node_modules is committed")
+	}
+	if s.binaryFiles > 0 {
+		score -= min(30, 10*s.binaryFiles)
+		issues = append(issues, fmt.Sprintf("%d large or binary files", s.binaryFiles))
+	}
+
+	notes := "No obvious repository bloat"
+	if len(issues) > 0 {
+		notes = capitalize(strings.Join(issues, "; "))
+	}
+	return models.ReviewCategory{Name: models.CategoryPerformance, Score: clampScore(score), Notes: notes}
+}
+
+func scoreMaintainability(s *repoStats) models.ReviewCategory {
+	if s.sourceFiles == 0 {
+		return models.ReviewCategory{Name: models.CategoryMaintainability, Score: 0, Notes: "No source code found"}
+	}
+	avg := s.totalLines / s.sourceFiles
+	score := 100
+	if avg > 200 {
+		score -= (avg - 200) / 8
+	}
+	if s.largestFile > 1000 {
+		score -= 15
+	}
+	return models.ReviewCategory{
+		Name:  models.CategoryMaintainability,
+		Score: clampScore(max(score, 20)),
+		Notes: fmt.Sprintf("Average file length is %d lines; largest file has %d lines", avg, s.largestFile),
+	}
+}
+
+// matchRequirements checks each requirement's keywords against the source corpus.
+// A requirement counts as addressed when at least half of its keywords appear.
+func matchRequirements(requirements models.Requirements, corpus string) (met, unmet []string) {
+	for _, req := range requirements {
+		keywords := requirementKeywords(req)
+		if len(keywords) == 0 {
+			met = append(met, req)
+			continue
+		}
+		found := 0
+		for _, kw := range keywords {
+			if strings.Contains(corpus, kw) {
+				found++
+			}
+		}
+		if 2*found >= len(keywords) {
+			met = append(met, req)
+		} else {
+			unmet = append(unmet, req)
+		}
+	}
+	return met, unmet
+}
+
+// requirementKeywords extracts the distinctive lowercase words of a requirement
+func requirementKeywords(req string) []string {
+	seen := map[string]bool{}
+	words := strings.FieldsFunc(strings.ToLower(req), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if len(w) >= 4 && !requirementStopwords[w] {
+			seen[w] = true
+		}
+	}
+
+	keywords := make([]string, 0, len(seen))
+	for w := range seen {
+		keywords = append(keywords, w)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
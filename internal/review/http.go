@@ -0,0 +1,225 @@
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/worker"
+)
+
+const (
+	maxUploadedFiles = 200
+	maxUploadedBytes = 1024 * 1024
+)
+
+// standardCategories are the category names a review service may return
+var standardCategories = map[string]bool{
+	models.CategoryCodeQuality:     true,
+	models.CategoryBestPractices:   true,
+	models.CategoryArchitecture:    true,
+	models.CategoryDocumentation:   true,
+	models.CategoryTestCoverage:    true,
+	models.CategorySecurity:        true,
+	models.CategoryPerformance:     true,
+	models.CategoryMaintainability: true,
+}
+
+// HTTPReviewer delegates reviews to an external service (e.g. an LLM gateway)
+// that accepts a JSON review request and answers with an AI review
+type HTTPReviewer struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+// httpReviewRequest is the payload posted to the review service
+type httpReviewRequest struct {
+	Submission struct {
+		ID          string `json:"id"`
+		ChallengeID string `json:"challenge_id"`
+		RepoURL     string `json:"repo_url"`
+		Branch      string `json:"branch"`
+		CommitHash  string `json:"commit_hash"`
+	} `json:"submission"`
+	Requirements []string     `json:"requirements"`
+	Files        []sourceFile `json:"files"`
+}
+
+type sourceFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// httpReviewResponse is the review returned by the service
+type httpReviewResponse struct {
+	OverallScore int                     `json:"overall_score"`
+	Categories   []models.ReviewCategory `json:"categories"`
+	Feedback     string                  `json:"feedback"`
+	Suggestions  []string                `json:"suggestions"`
+}
+
+// NewHTTPReviewer creates a reviewer that posts to endpoint
+func NewHTTPReviewer(endpoint, apiKey string, timeout time.Duration) (*HTTPReviewer, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid reviewer endpoint: %q", endpoint)
+	}
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+
+	return &HTTPReviewer{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Review sends the submission and its source files to the review service
+func (r *HTTPReviewer) Review(ctx context.Context, in Input) (*models.AIReview, error) {
+	var payload httpReviewRequest
+	payload.Submission.ID = in.Submission.ID
+	payload.Submission.ChallengeID = in.Submission.ChallengeID
+	payload.Submission.RepoURL = in.Submission.RepoURL
+	payload.Submission.Branch = in.Submission.Branch
+	payload.Submission.CommitHash = in.Submission.CommitHash
+	payload.Requirements = []string(in.Requirements)
+	if payload.Requirements == nil {
+		payload.Requirements = []string{}
+	}
+
+	payload.Files = []sourceFile{}
+	if in.SourceDir != "" {
+		files, err := collectSourceFiles(ctx, in.SourceDir)
+		if err != nil {
+			return nil, err
+		}
+		payload.Files = files
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode review request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build review request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("review service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1024*1024))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read review response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("review service returned %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+		// Server errors and rate limiting are worth retrying; other client errors are not
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, err
+		}
+		return nil, worker.Permanent(err)
+	}
+
+	var result httpReviewResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("invalid review response: %w", err)
+	}
+	if result.OverallScore < 0 || result.OverallScore > 100 {
+		return nil, fmt.Errorf("invalid review response: overall_score %d out of range", result.OverallScore)
+	}
+	for _, cat := range result.Categories {
+		if !standardCategories[cat.Name] {
+			return nil, fmt.Errorf("invalid review response: unknown category %q", cat.Name)
+		}
+		if cat.Score < 0 || cat.Score > 100 {
+			return nil, fmt.Errorf("invalid review response: %s score %d out of range", cat.Name, cat.Score)
+		}
+	}
+
+	suggestions := models.Suggestions(result.Suggestions)
+	if suggestions == nil {
+		suggestions = models.Suggestions{}
+	}
+
+	return &models.AIReview{
+		SubmissionID: in.Submission.ID,
+		OverallScore: result.OverallScore,
+		Categories:   models.ReviewCategories(result.Categories),
+		Feedback:     result.Feedback,
+		Suggestions:  suggestions,
+	}, nil
+}
+
+// collectSourceFiles gathers the text files worth sending for review, bounded in
+// count and total size
+func collectSourceFiles(ctx context.Context, root string) ([]sourceFile, error) {
+	files := []sourceFile{}
+	total := 0
+
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if path != root && skippedDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		name := d.Name()
+		ext := strings.ToLower(filepath.Ext(name))
+		if !sourceExtensions[ext] && !manifestFiles[name] && !strings.HasPrefix(strings.ToLower(name), "readme") {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !isText(data) || total+len(data) > maxUploadedBytes {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		files = append(files, sourceFile{Path: filepath.ToSlash(rel), Content: string(data)})
+		total += len(data)
+		if len(files) >= maxUploadedFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect source files: %w", err)
+	}
+
+	return files, nil
+}
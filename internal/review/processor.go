@@ -0,0 +1,100 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/fetcher"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+	"github.com/KBM2795/DevArena-Backend/internal/scoring"
+	"github.com/KBM2795/DevArena-Backend/internal/streaks"
+	"github.com/KBM2795/DevArena-Backend/internal/worker"
+	"github.com/google/uuid"
+)
+
+// Processor is the worker.Handler that checks out claimed submissions, runs a
+// Reviewer over them, scores the result and stores the AI review
+type Processor struct {
+	db       *db.Database
+	fetcher  *fetcher.Fetcher
+	reviewer Reviewer
+	streaks  *streaks.Tracker
+}
+
+// NewProcessor creates a review processor. A nil fetcher reviews submissions
+// without their source code; a nil tracker leaves streaks untouched.
+func NewProcessor(database *db.Database, repoFetcher *fetcher.Fetcher, reviewer Reviewer, tracker *streaks.Tracker) *Processor {
+	return &Processor{
+		db:       database,
+		fetcher:  repoFetcher,
+		reviewer: reviewer,
+		streaks:  tracker,
+	}
+}
+
+// Process reviews a submission against its challenge requirements
+func (p *Processor) Process(ctx context.Context, submission *models.Submission) error {
+	challenge, err := p.db.GetChallengeForReview(ctx, submission.ChallengeID)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			return worker.Permanent(fmt.Errorf("challenge %s no longer exists", submission.ChallengeID))
+		}
+		return err
+	}
+
+	input := Input{
+		Submission:   *submission,
+		Requirements: challenge.Requirements,
+	}
+
+	if p.fetcher != nil {
+		ws, err := p.fetcher.Fetch(ctx, submission.RepoURL, submission.Branch, submission.CommitHash)
+		if err != nil {
+			if errors.Is(err, fetcher.ErrInvalidRepository) || errors.Is(err, fetcher.ErrRepositoryTooLarge) {
+				return worker.Permanent(err)
+			}
+			return err
+		}
+		defer func() {
+			if err := ws.Cleanup(); err != nil {
+				log.Printf("Failed to clean up workspace for submission %s: %v", submission.ID, err)
+			}
+		}()
+
+		// Pin the exact commit so the review can be traced back to the code it saw
+		if ws.CommitHash != submission.CommitHash {
+			if err := p.db.PinSubmissionCommit(ctx, submission.ID, ws.CommitHash); err != nil {
+				return err
+			}
+			submission.CommitHash = ws.CommitHash
+			input.Submission.CommitHash = ws.CommitHash
+		}
+		input.SourceDir = ws.Dir
+	}
+
+	review, err := p.reviewer.Review(ctx, input)
+	if err != nil {
+		return err
+	}
+
+	review.ID = uuid.New().String()
+	review.SubmissionID = submission.ID
+	review.ReviewedAt = time.Now()
+
+	score := scoring.Score(review, challenge.CategoryWeights, challenge.MaxScore)
+	if err := p.db.SaveReviewResult(ctx, review, score, scoring.Passed(score, challenge.MaxScore)); err != nil {
+		return err
+	}
+
+	// The review is stored; a streak update failure must not trigger a retry
+	if p.streaks != nil {
+		if err := p.streaks.Record(ctx, submission.UserID); err != nil {
+			log.Printf("Failed to update streak for user %s: %v", submission.UserID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package review
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/KBM2795/DevArena-Backend/internal/config"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// Reviewer providers accepted in config.Reviewer.Provider
+const (
+	ProviderHeuristic = "heuristic"
+	ProviderHTTP      = "http"
+)
+
+// Input is everything a reviewer gets to look at for one submission
+type Input struct {
+	Submission   models.Submission
+	Requirements models.Requirements
+	SourceDir    string // Checked-out repository, empty when the code was not fetched
+}
+
+// Reviewer produces an AI review for a submission. Implementations fill in
+// OverallScore, Categories (using the standard Category* names), Feedback and
+// Suggestions; the caller assigns IDs and persists the result.
+type Reviewer interface {
+	Review(ctx context.Context, in Input) (*models.AIReview, error)
+}
+
+// NewReviewer builds the reviewer selected by the configuration
+func NewReviewer(cfg config.Reviewer) (Reviewer, error) {
+	switch cfg.Provider {
+	case "", ProviderHeuristic:
+		return NewHeuristicReviewer(), nil
+	case ProviderHTTP:
+		return NewHTTPReviewer(cfg.Endpoint, cfg.APIKey, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown reviewer provider: %s", cfg.Provider)
+	}
+}
+
+// clampScore keeps a score within 0-100
+func clampScore(score int) int {
+	return max(0, min(100, score))
+}
@@ -0,0 +1,63 @@
+package scoring
+
+import (
+	"math"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// PassPercent is the share of a challenge's max score a submission needs to count
+// as passing, e.g. for starter pack progress
+const PassPercent = 70
+
+// DefaultWeights apply when a challenge does not define its own category weights.
+// Correctness-related categories count more than polish.
+var DefaultWeights = models.CategoryWeights{
+	models.CategoryCodeQuality:     2,
+	models.CategoryBestPractices:   1.5,
+	models.CategoryArchitecture:    1.5,
+	models.CategorySecurity:        1.5,
+	models.CategoryMaintainability: 1,
+	models.CategoryTestCoverage:    1,
+	models.CategoryPerformance:     1,
+	models.CategoryDocumentation:   0.5,
+}
+
+// WeightedScore combines the review's category scores (0-100) using weights.
+// Categories without a positive weight are ignored. When no weighted category is
+// present the review's OverallScore is used as is.
+func WeightedScore(review *models.AIReview, weights models.CategoryWeights) float64 {
+	if len(weights) == 0 {
+		weights = DefaultWeights
+	}
+
+	var sum, totalWeight float64
+	for _, cat := range review.Categories {
+		weight := weights[cat.Name]
+		if weight <= 0 {
+			continue
+		}
+		sum += weight * float64(max(0, min(100, cat.Score)))
+		totalWeight += weight
+	}
+
+	if totalWeight == 0 {
+		return float64(max(0, min(100, review.OverallScore)))
+	}
+	return sum / totalWeight
+}
+
+// Score returns the submission score for a review: the weighted category score
+// scaled from 0-100 to 0-maxScore and rounded to the nearest point
+func Score(review *models.AIReview, weights models.CategoryWeights, maxScore int) int {
+	if maxScore <= 0 {
+		return 0
+	}
+	scaled := WeightedScore(review, weights) / 100 * float64(maxScore)
+	return int(math.Round(scaled))
+}
+
+// Passed reports whether score reaches PassPercent of maxScore
+func Passed(score, maxScore int) bool {
+	return maxScore > 0 && score*100 >= PassPercent*maxScore
+}
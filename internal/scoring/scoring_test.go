@@ -0,0 +1,114 @@
+package scoring
+
+import (
+	"math"
+	"testing"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// review builds a review from category name and score pairs
+func review(overall int, categories map[string]int) *models.AIReview {
+	r := &models.AIReview{OverallScore: overall}
+	for name, score := range categories {
+		r.Categories = append(r.Categories, models.ReviewCategory{Name: name, Score: score})
+	}
+	return r
+}
+
+var mixedReview = review(75, map[string]int{
+	models.CategoryCodeQuality:   80,
+	models.CategoryDocumentation: 40,
+	models.CategorySecurity:      100,
+})
+
+func TestWeightedScore(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		review  *models.AIReview
+		weights models.CategoryWeights
+		want    float64
+	}{
+		// (2*80 + 0.5*40 + 1.5*100) / (2 + 0.5 + 1.5)
+		{"default weights", mixedReview, nil, 82.5},
+		{"empty weights use the defaults", mixedReview, models.CategoryWeights{}, 82.5},
+		{"custom weights", mixedReview, models.CategoryWeights{
+			models.CategoryCodeQuality:   1,
+			models.CategoryDocumentation: 1,
+		}, 60},
+		{"zero and negative weights are ignored", mixedReview, models.CategoryWeights{
+			models.CategoryCodeQuality:   -1,
+			models.CategorySecurity:      0,
+			models.CategoryDocumentation: 2,
+		}, 40},
+		{"only zero weights fall back to the overall score", mixedReview, models.CategoryWeights{
+			models.CategorySecurity: 0,
+		}, 75},
+		{"no weighted category present", review(64, map[string]int{"Style": 90}), nil, 64},
+		{"category scores are clamped", review(0, map[string]int{
+			models.CategoryCodeQuality: 150,
+			models.CategorySecurity:    -20,
+		}), models.CategoryWeights{models.CategoryCodeQuality: 1, models.CategorySecurity: 1}, 50},
+		{"overall score is clamped", review(120, nil), nil, 100},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WeightedScore(tt.review, tt.weights); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("WeightedScore = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	custom := models.CategoryWeights{models.CategoryCodeQuality: 1, models.CategoryDocumentation: 1}
+
+	for _, tt := range []struct {
+		name     string
+		review   *models.AIReview
+		weights  models.CategoryWeights
+		maxScore int
+		want     int
+	}{
+		{"max score 100", mixedReview, custom, 100, 60},
+		{"scaled up", mixedReview, nil, 200, 165},
+		{"scaled down and rounded", mixedReview, nil, 50, 41},
+		{"scaled with custom weights", mixedReview, custom, 150, 90},
+		{"zero max score", mixedReview, nil, 0, 0},
+		{"negative max score", mixedReview, nil, -100, 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.review, tt.weights, tt.maxScore); got != tt.want {
+				t.Errorf("Score = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPassed(t *testing.T) {
+	for _, tt := range []struct {
+		score, maxScore int
+		want            bool
+	}{
+		{70, 100, true},
+		{69, 100, false},
+		{100, 100, true},
+		{140, 200, true},
+		{139, 200, false},
+		{7, 10, true},
+		{6, 10, false},
+		{0, 0, false},
+		{100, 0, false},
+	} {
+		if got := Passed(tt.score, tt.maxScore); got != tt.want {
+			t.Errorf("Passed(%d, %d) = %v, want %v", tt.score, tt.maxScore, got, tt.want)
+		}
+	}
+
+	// A review at exactly PassPercent passes once scored
+	boundary := review(0, map[string]int{models.CategoryCodeQuality: PassPercent})
+	for _, maxScore := range []int{10, 100, 250} {
+		if score := Score(boundary, nil, maxScore); !Passed(score, maxScore) {
+			t.Errorf("Passed(%d, %d) = false for a review at %d%%", score, maxScore, PassPercent)
+		}
+	}
+}
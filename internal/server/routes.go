@@ -6,12 +6,13 @@ import (
 
 	"github.com/KBM2795/DevArena-Backend/internal/auth/middleware"
 	"github.com/KBM2795/DevArena-Backend/internal/handlers"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
 	"github.com/KBM2795/DevArena-Backend/internal/webhooks"
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterRoutes sets up all application routes
-func (s *Server) RegisterRoutes() {
+func (s *Server) RegisterRoutes() error {
 	// Health check - outside of API versioning
 	s.router.GET("/health", handlers.HealthHandler)
 
@@ -21,15 +22,47 @@ func (s *Server) RegisterRoutes() {
 	// API v1 routes
 	v1 := s.router.Group("/api/v1")
 	{
-		// Public routes (no auth required)
-		s.registerPublicRoutes(v1)
+		var jwks *middleware.JWKS
+		if s.config.Clerk.JWKSURL != "" {
+			jwks = middleware.NewJWKS(s.config.Clerk.JWKSURL, nil, s.config.Clerk.JWKSCacheTTL, s.config.Clerk.JWKSMinRefresh)
+		}
+		jwtMiddleware, err := middleware.NewJWTMiddleware(s.config.Clerk.PEMPublicKey, s.config.Clerk.AuthorizedParties, jwks, middleware.TokenValidation{
+			Leeway:   s.config.Clerk.Leeway,
+			Issuer:   s.config.Clerk.Issuer,
+			Audience: s.config.Clerk.Audience,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create JWT middleware: %w", err)
+		}
+
+		// Signed-in users get a users row even before the Clerk webhook syncs them
+		provisionUser := middleware.ProvisionUser(s.db)
+
+		// Roles come from the users table, or from Clerk metadata when configured
+		roles := middleware.NewRoleAuthorizer(s.db, s.config.Roles.Admins, s.config.Roles.FromMetadata)
+
+		// Public routes (no auth)
+		public := v1.Group("/")
+		s.registerPublicRoutes(public)
+
+		// Personalized routes (no auth required, personalized when signed in). Kept
+		// apart from the public routes so a stale session cookie only affects these.
+		personalized := v1.Group("/")
+		personalized.Use(jwtMiddleware.OptionalAuthenticate(), provisionUser)
+		s.registerPersonalizedRoutes(personalized)
 
 		// Protected routes (auth required)
 		protected := v1.Group("/")
-		jwtMiddleware, _ := middleware.NewJWTMiddleware(s.config.Clerk.PEMPublicKey, s.config.Clerk.AuthorizedParties)
-		protected.Use(jwtMiddleware.Authenticate())
+		protected.Use(jwtMiddleware.Authenticate(), provisionUser)
 		s.registerProtectedRoutes(protected)
+
+		// Admin routes (auth required, admins only)
+		admin := v1.Group("/admin")
+		admin.Use(jwtMiddleware.Authenticate(), provisionUser, roles.RequireRole(models.RoleAdmin))
+		s.registerAdminRoutes(admin)
 	}
+
+	return nil
 }
 
 // registerWebhookRoutes registers webhook endpoints
@@ -42,6 +75,7 @@ func (s *Server) registerWebhookRoutes() {
 
 // registerPublicRoutes registers routes that don't require authentication
 func (s *Server) registerPublicRoutes(rg *gin.RouterGroup) {
+	h := handlers.NewHandlers(s.db, s.streaks)
 
 	rg.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
@@ -50,12 +84,34 @@ func (s *Server) registerPublicRoutes(rg *gin.RouterGroup) {
 		})
 	})
 
+	// Tag taxonomy
+	rg.GET("/tags", h.ListTagsHandler)
+
+	// Onboarding vocabulary
+	rg.GET("/onboarding/options", h.OnboardingOptionsHandler)
+
+	// Leaderboard
+	rg.GET("/leaderboard", h.LeaderboardHandler)
+
+	// Public user profiles
+	rg.GET("/users/:username", h.GetUserProfileHandler)
+	rg.GET("/users/:username/activity", h.GetUserActivityHandler)
+}
+
+// registerPersonalizedRoutes registers public routes that include the caller's
+// progress when they are signed in
+func (s *Server) registerPersonalizedRoutes(rg *gin.RouterGroup) {
+	h := handlers.NewHandlers(s.db, s.streaks)
+
+	// Challenge catalog, with whether the caller has solved each challenge
+	rg.GET("/challenges", h.ListChallengesHandler)
+	rg.GET("/challenges/:id", h.GetChallengeHandler)
 }
 
 // registerProtectedRoutes registers routes that require authentication
 func (s *Server) registerProtectedRoutes(rg *gin.RouterGroup) {
 	// Create handlers with database dependency
-	h := handlers.NewHandlers(s.db)
+	h := handlers.NewHandlers(s.db, s.streaks)
 
 	rg.GET("/protected", func(c *gin.Context) {
 		userID, exists := middleware.GetUserID(c)
@@ -68,7 +124,48 @@ func (s *Server) registerProtectedRoutes(rg *gin.RouterGroup) {
 		})
 	})
 
+	// Current user profile
+	rg.GET("/me", h.GetMeHandler)
+	rg.PATCH("/me", h.UpdateMeHandler)
+
 	// Onboarding routes
 	rg.POST("/onboarding", h.OnboardingHandler)
+
+	// Starter packs
+	rg.GET("/starter-pack", h.GetStarterPackHandler)
+	rg.GET("/starter-packs", h.ListStarterPacksHandler)
+	rg.POST("/starter-pack/regenerate", h.RegenerateStarterPackHandler)
+	rg.POST("/starter-pack/archive", h.ArchiveStarterPackHandler)
+	rg.POST("/starter-pack/reset", h.ResetStarterPackHandler)
+
+	// Submission routes
+	rg.POST("/submissions", h.CreateSubmissionHandler)
+	rg.GET("/submissions", h.ListSubmissionsHandler)
+	rg.GET("/submissions/:id", h.GetSubmissionHandler)
+
+	// Leaderboard position of the caller
+	rg.GET("/leaderboard/me", h.MyLeaderboardPositionHandler)
 	
 }
+
+// registerAdminRoutes registers content and user management routes
+func (s *Server) registerAdminRoutes(rg *gin.RouterGroup) {
+	h := handlers.NewHandlers(s.db, s.streaks)
+
+	// Tags
+	rg.POST("/tags", h.CreateTagHandler)
+	rg.PATCH("/tags/:id", h.UpdateTagHandler)
+	rg.POST("/tags/:id/merge", h.MergeTagsHandler)
+
+	// Challenge authoring
+	rg.GET("/challenges", h.AdminListChallengesHandler)
+	rg.GET("/challenges/:id", h.AdminGetChallengeHandler)
+	rg.POST("/challenges", h.AdminCreateChallengeHandler)
+	rg.PATCH("/challenges/:id", h.AdminUpdateChallengeHandler)
+	rg.DELETE("/challenges/:id", h.AdminDeleteChallengeHandler)
+	rg.POST("/challenges/:id/publish", h.AdminPublishChallengeHandler)
+	rg.POST("/challenges/:id/unpublish", h.AdminUnpublishChallengeHandler)
+
+	// Users
+	rg.PATCH("/users/:username/role", h.UpdateUserRoleHandler)
+}
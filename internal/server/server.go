@@ -12,6 +12,11 @@ import (
 
 	"github.com/KBM2795/DevArena-Backend/internal/config"
 	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/fetcher"
+	"github.com/KBM2795/DevArena-Backend/internal/jobs"
+	"github.com/KBM2795/DevArena-Backend/internal/review"
+	"github.com/KBM2795/DevArena-Backend/internal/streaks"
+	"github.com/KBM2795/DevArena-Backend/internal/worker"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -21,9 +26,18 @@ type Server struct {
 	db         *db.Database
 	config     *config.Config
 	httpServer *http.Server
+
+	// Background review processing; the pool only runs when a handler is configured
+	reviewHandler worker.Handler
+	reviewPool    *worker.Pool
+
+	// Periodic maintenance jobs such as leaderboard snapshots
+	scheduler *jobs.Scheduler
+
+	streaks *streaks.Tracker
 }
 
-func NewServer(cfg *config.Config, db *db.Database) *Server {
+func NewServer(cfg *config.Config, db *db.Database) (*Server, error) {
 	// Set Gin mode based on environment
 	if cfg.Env != "Dev" {
 		gin.SetMode(gin.ReleaseMode)
@@ -51,13 +65,26 @@ func NewServer(cfg *config.Config, db *db.Database) *Server {
 	}))
 
 	server := &Server{
-		router: router,
-		db:     db,
-		config: cfg,
+		router:    router,
+		db:        db,
+		config:    cfg,
+		scheduler: jobs.NewScheduler(),
+		streaks:   streaks.NewTracker(db, cfg.Streaks),
+	}
+
+	reviewer, err := review.NewReviewer(cfg.Reviewer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create reviewer: %w", err)
 	}
+	server.reviewHandler = review.NewProcessor(db, fetcher.New(cfg.Fetcher), reviewer, server.streaks)
+
+	server.scheduler.Add("leaderboard-snapshots", cfg.Leaderboard.RefreshInterval, db.RefreshLeaderboardSnapshots)
+	server.scheduler.Add("streak-reset", cfg.Streaks.ResetInterval, server.streaks.ResetBroken)
 
-	server.RegisterRoutes()
-	return server
+	if err := server.RegisterRoutes(); err != nil {
+		return nil, err
+	}
+	return server, nil
 }
 
 func (s *Server) Run() error {
@@ -68,6 +95,15 @@ func (s *Server) Run() error {
 		Handler: s.router,
 	}
 
+	// Start background review workers
+	if s.reviewHandler != nil {
+		s.reviewPool = worker.NewPool(s.db, s.reviewHandler, s.config.Worker)
+		s.reviewPool.Start()
+	} else {
+		log.Println("No review handler configured, review workers disabled")
+	}
+	s.scheduler.Start()
+
 	// Channel to listen for errors from the server
 	serverErrors := make(chan error, 1)
 
@@ -84,6 +120,7 @@ func (s *Server) Run() error {
 	// Block until we receive a signal or server error
 	select {
 	case err := <-serverErrors:
+		s.stopWorkers(context.Background())
 		if err != http.ErrServerClosed {
 			return fmt.Errorf("server error: %w", err)
 		}
@@ -99,11 +136,28 @@ func (s *Server) Run() error {
 		if err := s.httpServer.Shutdown(ctx); err != nil {
 			// Force shutdown if graceful shutdown fails
 			s.httpServer.Close()
+			s.stopWorkers(ctx)
 			return fmt.Errorf("could not stop server gracefully: %w", err)
 		}
 
+		// Let in-flight reviews finish within the same shutdown budget
+		s.stopWorkers(ctx)
+
 		log.Println("Server stopped gracefully")
 	}
 
 	return nil
 }
+
+// stopWorkers stops the scheduled jobs and drains the review worker pool
+func (s *Server) stopWorkers(ctx context.Context) {
+	if err := s.scheduler.Shutdown(ctx); err != nil {
+		log.Printf("Scheduler: %v", err)
+	}
+	if s.reviewPool == nil {
+		return
+	}
+	if err := s.reviewPool.Shutdown(ctx); err != nil {
+		log.Printf("Review workers: %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package starterpack
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// PackSize is the number of challenges in a generated starter pack
+const PackSize = 5
+
+// Preferences are the onboarding answers a pack is generated from
+type Preferences struct {
+	Experience   string
+	Paths        []string
+	Technologies []string
+}
+
+// Candidate is a published challenge that can be recommended
+type Candidate struct {
+	ID             string
+	Difficulty     models.Difficulty
+	TechStack      []string
+	Tags           []models.Tag
+	EstimatedHours int
+}
+
+// difficultyMix is how many challenges of each difficulty a pack aims for per
+// experience level, listed easiest first
+var difficultyMix = map[string][3]int{
+	models.ExperienceBeginner:     {3, 2, 0},
+	models.ExperienceIntermediate: {1, 3, 1},
+	models.ExperienceAdvanced:     {0, 2, 3},
+	models.ExperienceExpert:       {0, 1, 4},
+}
+
+var difficultyOrder = []models.Difficulty{models.DifficultyEasy, models.DifficultyMedium, models.DifficultyHard}
+
+// pathCategories maps an onboarding path to the tag categories it covers
+var pathCategories = map[string][]string{
+	models.PathFrontend:  {"frontend"},
+	models.PathBackend:   {"backend", "database"},
+	models.PathFullstack: {"frontend", "backend", "database"},
+	models.PathAI:        {"ai"},
+	models.PathMobile:    {"mobile", "frontend"},
+}
+
+// aliases folds common spellings of a technology onto one key
+var aliases = map[string]string{
+	"go":       "golang",
+	"node":     "nodejs",
+	"js":       "javascript",
+	"ts":       "typescript",
+	"postgres": "postgresql",
+	"reactjs":  "react",
+	"nextjs":   "next",
+	"threejs":  "three",
+}
+
+// normalize reduces a technology or tag name to a comparable key, so "Node.js",
+// "nodejs" and "node" all match
+func normalize(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	key := b.String()
+	if alias, ok := aliases[key]; ok {
+		return alias
+	}
+	return key
+}
+
+type scored struct {
+	Candidate
+	relevance int
+	level     int // Index into difficultyOrder
+}
+
+// relevance scores how well a challenge matches the preferences. A technology
+// match counts more than a path match; zero means unrelated.
+func relevance(c Candidate, techs, categories map[string]bool) int {
+	score := 0
+	seen := map[string]bool{}
+	match := func(name string) {
+		key := normalize(name)
+		if key != "" && techs[key] && !seen[key] {
+			seen[key] = true
+			score += 3
+		}
+	}
+	for _, t := range c.TechStack {
+		match(t)
+	}
+	for _, tag := range c.Tags {
+		match(tag.Name)
+		match(tag.Slug)
+	}
+
+	for _, tag := range c.Tags {
+		if categories[strings.ToLower(tag.Category)] {
+			score++
+			break
+		}
+	}
+	return score
+}
+
+// Recommend picks up to PackSize challenges for the preferences and returns their
+// IDs ordered as the user should take them: easiest first, then most relevant.
+// Challenges matching the chosen technologies or paths are preferred; when too
+// few match, the pack is topped up with the remaining challenges so it is never
+// empty while the catalog is not. Experience levels it does not know are treated
+// as intermediate.
+func Recommend(prefs Preferences, candidates []Candidate) []string {
+	mix, ok := difficultyMix[strings.ToLower(prefs.Experience)]
+	if !ok {
+		mix = difficultyMix[models.ExperienceIntermediate]
+	}
+
+	techs := map[string]bool{}
+	for _, t := range prefs.Technologies {
+		if key := normalize(t); key != "" {
+			techs[key] = true
+		}
+	}
+	categories := map[string]bool{}
+	for _, p := range prefs.Paths {
+		for _, cat := range pathCategories[strings.ToLower(p)] {
+			categories[cat] = true
+		}
+	}
+
+	// Bucket candidates by difficulty, most relevant first
+	buckets := make([][]scored, len(difficultyOrder))
+	for _, c := range candidates {
+		level := slices.Index(difficultyOrder, c.Difficulty)
+		if level < 0 {
+			continue
+		}
+		buckets[level] = append(buckets[level], scored{
+			Candidate: c,
+			relevance: relevance(c, techs, categories),
+			level:     level,
+		})
+	}
+	for _, bucket := range buckets {
+		slices.SortFunc(bucket, func(a, b scored) int {
+			if a.relevance != b.relevance {
+				return b.relevance - a.relevance
+			}
+			if a.EstimatedHours != b.EstimatedHours {
+				return a.EstimatedHours - b.EstimatedHours
+			}
+			return strings.Compare(a.ID, b.ID)
+		})
+	}
+
+	var picked []scored
+	take := func(level int, onlyRelevant bool) bool {
+		bucket := buckets[level]
+		if len(bucket) == 0 || (onlyRelevant && bucket[0].relevance == 0) {
+			return false
+		}
+		picked = append(picked, bucket[0])
+		buckets[level] = bucket[1:]
+		return true
+	}
+
+	// Fill each difficulty's quota with relevant challenges, then fill the gaps
+	// from the nearest difficulty, then fall back to unrelated challenges
+	for level, quota := range mix {
+		for n := 0; n < quota; n++ {
+			if !take(level, true) {
+				break
+			}
+		}
+	}
+	for _, onlyRelevant := range []bool{true, false} {
+		for _, level := range nearestLevels(mix) {
+			for len(picked) < PackSize {
+				if !take(level, onlyRelevant) {
+					break
+				}
+			}
+		}
+	}
+
+	slices.SortStableFunc(picked, func(a, b scored) int {
+		if a.level != b.level {
+			return a.level - b.level
+		}
+		return b.relevance - a.relevance
+	})
+
+	ids := make([]string, len(picked))
+	for i, s := range picked {
+		ids[i] = s.ID
+	}
+	return ids
+}
+
+// nearestLevels orders difficulty levels by distance from the center of the mix,
+// so gaps are filled with challenges close to the intended difficulty
+func nearestLevels(mix [3]int) []int {
+	weighted, total := 0, 0
+	for level, n := range mix {
+		weighted += level * n
+		total += n
+	}
+	center := float64(weighted) / float64(max(total, 1))
+
+	levels := []int{0, 1, 2}
+	slices.SortStableFunc(levels, func(a, b int) int {
+		da, db := math.Abs(float64(a)-center), math.Abs(float64(b)-center)
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return 1
+		}
+		return 0
+	})
+	return levels
+}
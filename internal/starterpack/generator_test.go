@@ -0,0 +1,124 @@
+package starterpack
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// goCatalog has four equally relevant Go challenges per difficulty, named by
+// difficulty and ordered by estimated hours, e.g. easy1 before easy2
+func goCatalog() []Candidate {
+	var catalog []Candidate
+	for level, name := range []string{"easy", "medium", "hard"} {
+		for i := 1; i <= 4; i++ {
+			catalog = append(catalog, Candidate{
+				ID:             fmt.Sprintf("%s%d", name, i),
+				Difficulty:     difficultyOrder[level],
+				TechStack:      []string{"Go"},
+				EstimatedHours: i,
+			})
+		}
+	}
+	return catalog
+}
+
+func TestRecommendExperienceLevels(t *testing.T) {
+	for _, tt := range []struct {
+		experience string
+		want       []string
+	}{
+		{models.ExperienceBeginner, []string{"easy1", "easy2", "easy3", "medium1", "medium2"}},
+		{models.ExperienceIntermediate, []string{"easy1", "medium1", "medium2", "medium3", "hard1"}},
+		{models.ExperienceAdvanced, []string{"medium1", "medium2", "hard1", "hard2", "hard3"}},
+		{models.ExperienceExpert, []string{"medium1", "hard1", "hard2", "hard3", "hard4"}},
+		{"Beginner", []string{"easy1", "easy2", "easy3", "medium1", "medium2"}},
+		// Unknown levels are treated as intermediate
+		{"guru", []string{"easy1", "medium1", "medium2", "medium3", "hard1"}},
+		{"none", []string{"easy1", "medium1", "medium2", "medium3", "hard1"}},
+	} {
+		t.Run(tt.experience, func(t *testing.T) {
+			prefs := Preferences{Experience: tt.experience, Technologies: []string{"golang"}}
+			if got := Recommend(prefs, goCatalog()); !slices.Equal(got, tt.want) {
+				t.Errorf("Recommend = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendFillsGaps(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		prefs      Preferences
+		candidates []Candidate
+		want       []string
+	}{
+		{"empty catalog", Preferences{Experience: models.ExperienceBeginner}, nil, []string{}},
+		{
+			name:  "nearest difficulty before unrelated challenges",
+			prefs: Preferences{Experience: models.ExperienceExpert, Technologies: []string{"go"}},
+			candidates: []Candidate{
+				{ID: "easy-go", Difficulty: models.DifficultyEasy, TechStack: []string{"Go"}},
+				{ID: "medium-go", Difficulty: models.DifficultyMedium, TechStack: []string{"Go"}},
+				{ID: "hard-go", Difficulty: models.DifficultyHard, TechStack: []string{"Go"}},
+				{ID: "hard-rust", Difficulty: models.DifficultyHard, TechStack: []string{"Rust"}},
+				{ID: "medium-rust", Difficulty: models.DifficultyMedium, TechStack: []string{"Rust"}},
+				{ID: "easy-rust", Difficulty: models.DifficultyEasy, TechStack: []string{"Rust"}},
+			},
+			want: []string{"easy-go", "medium-go", "medium-rust", "hard-go", "hard-rust"},
+		},
+		{
+			name:  "unknown difficulties are skipped",
+			prefs: Preferences{Experience: models.ExperienceBeginner},
+			candidates: []Candidate{
+				{ID: "insane", Difficulty: "Insane"},
+				{ID: "easy", Difficulty: models.DifficultyEasy},
+			},
+			want: []string{"easy"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Recommend(tt.prefs, tt.candidates); !slices.Equal(got, tt.want) {
+				t.Errorf("Recommend = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecommendFoldsAliases(t *testing.T) {
+	backendTag := models.Tag{Name: "REST APIs", Slug: "rest-apis", Category: "Backend"}
+	candidates := []Candidate{
+		{ID: "python", Difficulty: models.DifficultyEasy, TechStack: []string{"Python"}, EstimatedHours: 1},
+		{ID: "postgres", Difficulty: models.DifficultyEasy, TechStack: []string{"PostgreSQL"}, EstimatedHours: 3},
+		// The same technology spelled twice counts once
+		{ID: "node-twice", Difficulty: models.DifficultyEasy, TechStack: []string{"nodejs", "Node.JS"}, EstimatedHours: 2},
+		// A technology plus a path match ranks first
+		{ID: "node-api", Difficulty: models.DifficultyEasy, TechStack: []string{"node"}, Tags: []models.Tag{backendTag}, EstimatedHours: 4},
+		{ID: "tagged-ts", Difficulty: models.DifficultyEasy, Tags: []models.Tag{{Name: "TypeScript", Slug: "ts"}}, EstimatedHours: 5},
+	}
+	prefs := Preferences{
+		Experience:   models.ExperienceBeginner,
+		Paths:        []string{"Backend"},
+		Technologies: []string{"Node.js", "postgres", "TS"},
+	}
+
+	want := []string{"node-api", "node-twice", "postgres", "tagged-ts", "python"}
+	if got := Recommend(prefs, candidates); !slices.Equal(got, want) {
+		t.Errorf("Recommend = %v, want %v", got, want)
+	}
+
+	for _, tt := range []struct{ a, b string }{
+		{"Node.js", "node"},
+		{"nodejs", "NODE"},
+		{"Postgres", "postgresql"},
+		{"Next.js", "nextjs"},
+		{"Go", "golang"},
+		{"TS", "TypeScript"},
+	} {
+		if normalize(tt.a) != normalize(tt.b) {
+			t.Errorf("normalize(%q) = %q, normalize(%q) = %q; want equal", tt.a, normalize(tt.a), tt.b, normalize(tt.b))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package streaks
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/config"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+)
+
+// State is a user's streak computed from their active days
+type State struct {
+	Current        int
+	Longest        int
+	LastActiveDate *time.Time // Latest active day, nil if the user was never active
+}
+
+// Compute derives the streak from distinct active days in ascending order, all
+// expressed as local dates at midnight in one location (the database returns them
+// in UTC). Days are compared as calendar dates, so a 23 or 25 hour DST day still
+// counts as consecutive. The current streak is the run of consecutive days ending
+// today or yesterday; a run ending yesterday is still alive because the user has
+// until the end of today to extend it.
+func Compute(days []time.Time, today time.Time) State {
+	var state State
+	run := 0
+	for i, day := range days {
+		if i > 0 && days[i-1].AddDate(0, 0, 1).Equal(day) {
+			run++
+		} else {
+			run = 1
+		}
+		state.Longest = max(state.Longest, run)
+	}
+
+	if len(days) == 0 {
+		return state
+	}
+	last := days[len(days)-1]
+	state.LastActiveDate = &last
+	if !last.Before(today.AddDate(0, 0, -1)) {
+		state.Current = run
+	}
+	return state
+}
+
+// Tracker keeps users.current_streak and longest_streak in sync with activity
+type Tracker struct {
+	db                  *db.Database
+	countAllSubmissions bool
+}
+
+// NewTracker creates a streak tracker
+func NewTracker(database *db.Database, cfg config.Streaks) *Tracker {
+	return &Tracker{
+		db:                  database,
+		countAllSubmissions: cfg.CountAllSubmissions,
+	}
+}
+
+// CountsAllSubmissions reports whether any submission, not only a reviewed one,
+// makes a day active
+func (t *Tracker) CountsAllSubmissions() bool {
+	return t.countAllSubmissions
+}
+
+// Record recomputes a user's streak after new activity. Recomputing from the
+// full history keeps the result correct when reviews complete out of order.
+func (t *Tracker) Record(ctx context.Context, userID string) error {
+	days, today, err := t.db.GetUserActiveDays(ctx, userID, t.countAllSubmissions)
+	if err != nil {
+		return err
+	}
+	state := Compute(days, today)
+	return t.db.UpdateUserStreak(ctx, userID, state.Current, state.Longest, state.LastActiveDate)
+}
+
+// ResetBroken zeroes the streak of every user who missed a full local day.
+// It is meant to run at least hourly so each time zone is reset soon after midnight.
+func (t *Tracker) ResetBroken(ctx context.Context) error {
+	n, err := t.db.ResetBrokenStreaks(ctx)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("Reset %d broken streaks", n)
+	}
+	return nil
+}
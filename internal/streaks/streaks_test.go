@@ -0,0 +1,66 @@
+package streaks
+
+import (
+	"testing"
+	"time"
+)
+
+// dates returns midnight of each "2006-01-02" day in loc
+func dates(t *testing.T, loc *time.Location, days ...string) []time.Time {
+	t.Helper()
+	out := make([]time.Time, len(days))
+	for i, d := range days {
+		day, err := time.ParseInLocation(time.DateOnly, d, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = day
+	}
+	return out
+}
+
+func TestCompute(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name        string
+		loc         *time.Location
+		days        []string
+		today       string
+		wantCurrent int
+		wantLongest int
+	}{
+		{"never active", time.UTC, nil, "2026-03-10", 0, 0},
+		{"active today", time.UTC, []string{"2026-03-10"}, "2026-03-10", 1, 1},
+		{"active yesterday", time.UTC, []string{"2026-03-09"}, "2026-03-10", 1, 1},
+		{"last active two days ago", time.UTC, []string{"2026-03-08"}, "2026-03-10", 0, 1},
+		{"run ending today", time.UTC, []string{"2026-03-08", "2026-03-09", "2026-03-10"}, "2026-03-10", 3, 3},
+		{"run ending yesterday", time.UTC, []string{"2026-03-07", "2026-03-08", "2026-03-09"}, "2026-03-10", 3, 3},
+		{"gap day resets the run", time.UTC, []string{"2026-03-05", "2026-03-06", "2026-03-07", "2026-03-09", "2026-03-10"}, "2026-03-10", 2, 3},
+		{"broken longest run is kept", time.UTC, []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-04"}, "2026-03-10", 0, 4},
+		{"month boundary", time.UTC, []string{"2026-02-27", "2026-02-28", "2026-03-01"}, "2026-03-01", 3, 3},
+		{"year boundary", time.UTC, []string{"2025-12-31", "2026-01-01"}, "2026-01-02", 2, 2},
+		{"DST starts", berlin, []string{"2026-03-28", "2026-03-29", "2026-03-30"}, "2026-03-30", 3, 3},
+		{"DST ends", berlin, []string{"2026-10-24", "2026-10-25", "2026-10-26"}, "2026-10-27", 3, 3},
+		{"gap across DST", berlin, []string{"2026-03-28", "2026-03-30"}, "2026-03-30", 1, 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			days := dates(t, tt.loc, tt.days...)
+			today := dates(t, tt.loc, tt.today)[0]
+
+			got := Compute(days, today)
+			if got.Current != tt.wantCurrent || got.Longest != tt.wantLongest {
+				t.Errorf("Compute = current %d, longest %d; want %d, %d", got.Current, got.Longest, tt.wantCurrent, tt.wantLongest)
+			}
+			switch {
+			case len(days) == 0 && got.LastActiveDate != nil:
+				t.Errorf("LastActiveDate = %v, want nil", got.LastActiveDate)
+			case len(days) > 0 && (got.LastActiveDate == nil || !got.LastActiveDate.Equal(days[len(days)-1])):
+				t.Errorf("LastActiveDate = %v, want %v", got.LastActiveDate, days[len(days)-1])
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/KBM2795/DevArena-Backend/internal/db"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 // ClerkWebhookHandler handles Clerk webhook events
@@ -206,14 +207,15 @@ func (h *ClerkWebhookHandler) handleUserCreated(c *gin.Context, data json.RawMes
 	}
 
 	// Insert user into database (or update if created just-in-time during onboarding)
-	// Use NULLIF to convert empty strings to NULL for UNIQUE constraint compatibility
+	// Use NULLIF to convert empty strings to NULL for UNIQUE constraint compatibility.
+	// The username belongs to DevArena once set (PATCH /me), so Clerk's only fills an empty one.
 	userID := uuid.New().String()
 	query := `
 		INSERT INTO users (id, clerk_user_id, email, username, display_name, avatar_url, github_username, github_connected, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW())
 		ON CONFLICT (clerk_user_id) DO UPDATE SET
 			email = EXCLUDED.email,
-			username = COALESCE(NULLIF(EXCLUDED.username, ''), users.username),
+			username = COALESCE(users.username, NULLIF(EXCLUDED.username, '')),
 			display_name = COALESCE(NULLIF(EXCLUDED.display_name, ''), users.display_name),
 			avatar_url = COALESCE(NULLIF(EXCLUDED.avatar_url, ''), users.avatar_url),
 			github_username = COALESCE(NULLIF(EXCLUDED.github_username, ''), users.github_username),
@@ -221,17 +223,32 @@ func (h *ClerkWebhookHandler) handleUserCreated(c *gin.Context, data json.RawMes
 			updated_at = NOW()
 	`
 
-	_, err := h.db.Pool.Exec(context.Background(), query,
-		userID,
-		userData.ID,
-		primaryEmail,
-		username,
-		displayName,
-		userData.ImageURL,
-		githubUsernamePtr,
-		githubUsername != "",
-	)
+	upsert := func(username *string) error {
+		_, err := h.db.Pool.Exec(context.Background(), query,
+			userID,
+			userData.ID,
+			primaryEmail,
+			username,
+			displayName,
+			userData.ImageURL,
+			githubUsernamePtr,
+			githubUsername != "",
+		)
+		return err
+	}
 
+	err := upsert(username)
+	if db.IsUniqueViolation(err) && username != nil {
+		// Another user already has this username (in any case); sync the rest without it
+		log.Printf("Username %q from Clerk is taken, syncing user %s without it", *username, userData.ID)
+		err = upsert(nil)
+	}
+	if db.IsUniqueViolation(err) {
+		// Svix retries any non-2xx response, and retrying cannot resolve a conflict
+		log.Printf("Not syncing user %s, it conflicts with an existing user: %v", userData.ID, err)
+		c.JSON(http.StatusOK, gin.H{"message": "User conflicts with an existing user, not synced"})
+		return
+	}
 	if err != nil {
 		log.Printf("Error inserting/updating user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
@@ -284,11 +301,12 @@ func (h *ClerkWebhookHandler) handleUserUpdated(c *gin.Context, data json.RawMes
 		githubUsernamePtr = &githubUsername
 	}
 
-	// Update user in database - use NULLIF to avoid empty string unique constraint issues
+	// Update user in database - use NULLIF to avoid empty string unique constraint issues.
+	// The username belongs to DevArena once set (PATCH /me), so Clerk's only fills an empty one.
 	query := `
 		UPDATE users 
 		SET email = $2, 
-			username = COALESCE(NULLIF($3, ''), username), 
+			username = COALESCE(username, NULLIF($3, '')), 
 			display_name = COALESCE(NULLIF($4, ''), display_name), 
 			avatar_url = COALESCE(NULLIF($5, ''), avatar_url), 
 			github_username = COALESCE(NULLIF($6, ''), github_username), 
@@ -297,16 +315,30 @@ func (h *ClerkWebhookHandler) handleUserUpdated(c *gin.Context, data json.RawMes
 		WHERE clerk_user_id = $1
 	`
 
-	result, err := h.db.Pool.Exec(context.Background(), query,
-		userData.ID,
-		primaryEmail,
-		username,
-		displayName,
-		userData.ImageURL,
-		githubUsernamePtr,
-		githubUsername != "",
-	)
+	update := func(username *string) (pgconn.CommandTag, error) {
+		return h.db.Pool.Exec(context.Background(), query,
+			userData.ID,
+			primaryEmail,
+			username,
+			displayName,
+			userData.ImageURL,
+			githubUsernamePtr,
+			githubUsername != "",
+		)
+	}
 
+	result, err := update(username)
+	if db.IsUniqueViolation(err) && username != nil {
+		// Another user already has this username (in any case); sync the rest without it
+		log.Printf("Username %q from Clerk is taken, syncing user %s without it", *username, userData.ID)
+		result, err = update(nil)
+	}
+	if db.IsUniqueViolation(err) {
+		// Svix retries any non-2xx response, and retrying cannot resolve a conflict
+		log.Printf("Not syncing user %s, it conflicts with an existing user: %v", userData.ID, err)
+		c.JSON(http.StatusOK, gin.H{"message": "User conflicts with an existing user, not synced"})
+		return
+	}
 	if err != nil {
 		log.Printf("Error updating user: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
@@ -0,0 +1,258 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/KBM2795/DevArena-Backend/internal/config"
+	"github.com/KBM2795/DevArena-Backend/internal/db"
+	"github.com/KBM2795/DevArena-Backend/internal/models"
+)
+
+// Handler reviews a single claimed submission. Returning nil marks the submission
+// as reviewed; returning an error schedules a retry unless the error is permanent.
+type Handler interface {
+	Process(ctx context.Context, submission *models.Submission) error
+}
+
+// HandlerFunc adapts a plain function to the Handler interface
+type HandlerFunc func(ctx context.Context, submission *models.Submission) error
+
+// Process calls f(ctx, submission)
+func (f HandlerFunc) Process(ctx context.Context, submission *models.Submission) error {
+	return f(ctx, submission)
+}
+
+// permanentError marks an error that retrying will not fix (invalid repo, etc.)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the pool fails the submission immediately instead of retrying
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped with Permanent
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
+
+// Pool runs a fixed number of workers that claim pending submissions from Postgres
+type Pool struct {
+	db      *db.Database
+	handler Handler
+	config  config.Worker
+
+	stop     context.CancelFunc // Stops claiming new work
+	abort    context.CancelFunc // Cancels in-flight reviews
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+// NewPool creates a worker pool, filling in defaults for unset config values
+func NewPool(database *db.Database, handler Handler, cfg config.Worker) *Pool {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = 1
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 5 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 3
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 30 * time.Second
+	}
+	if cfg.MaxBackoff < cfg.BaseBackoff {
+		cfg.MaxBackoff = cfg.BaseBackoff
+	}
+	if cfg.JobTimeout <= 0 {
+		cfg.JobTimeout = 5 * time.Minute
+	}
+	if cfg.StaleAfter <= cfg.JobTimeout {
+		cfg.StaleAfter = 3 * cfg.JobTimeout
+	}
+
+	return &Pool{
+		db:      database,
+		handler: handler,
+		config:  cfg,
+	}
+}
+
+// Start launches the workers. They keep polling until Shutdown is called.
+func (p *Pool) Start() {
+	pollCtx, stop := context.WithCancel(context.Background())
+	jobCtx, abort := context.WithCancel(context.Background())
+	p.stop = stop
+	p.abort = abort
+
+	// Recover reviews orphaned by a previous crash before taking new work
+	p.requeueStale(pollCtx)
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.config.StaleAfter / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-ticker.C:
+				p.requeueStale(pollCtx)
+			}
+		}
+	}()
+
+	for i := 0; i < p.config.Concurrency; i++ {
+		p.wg.Add(1)
+		go func(id int) {
+			defer p.wg.Done()
+			p.run(pollCtx, jobCtx, id)
+		}(i + 1)
+	}
+
+	log.Printf("Review worker pool started with %d workers", p.config.Concurrency)
+}
+
+// Shutdown stops claiming new submissions and waits for in-flight reviews to finish.
+// If ctx expires first, in-flight reviews are cancelled and released back to the queue.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	if p.stop == nil {
+		return nil
+	}
+
+	var err error
+	p.stopOnce.Do(func() {
+		p.stop()
+
+		done := make(chan struct{})
+		go func() {
+			p.wg.Wait()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			log.Println("Review worker pool drained")
+		case <-ctx.Done():
+			p.abort()
+			<-done
+			err = fmt.Errorf("review workers did not drain in time: %w", ctx.Err())
+		}
+		p.abort()
+	})
+	return err
+}
+
+// run is the loop executed by each worker
+func (p *Pool) run(pollCtx, jobCtx context.Context, id int) {
+	for {
+		if pollCtx.Err() != nil {
+			return
+		}
+
+		submission, err := p.db.ClaimPendingSubmission(pollCtx)
+		if err != nil && pollCtx.Err() == nil {
+			log.Printf("Worker %d: %v", id, err)
+		}
+
+		if submission == nil {
+			// Queue empty (or claim failed), wait before polling again
+			select {
+			case <-pollCtx.Done():
+				return
+			case <-time.After(p.config.PollInterval):
+			}
+			continue
+		}
+
+		p.process(jobCtx, id, submission)
+	}
+}
+
+// process reviews one submission and records the outcome
+func (p *Pool) process(jobCtx context.Context, id int, submission *models.Submission) {
+	log.Printf("Worker %d: reviewing submission %s (attempt %d)", id, submission.ID, submission.Attempts)
+
+	ctx, cancel := context.WithTimeout(jobCtx, p.config.JobTimeout)
+	err := p.handler.Process(ctx, submission)
+	cancel()
+
+	// Bookkeeping must succeed even when the job context was aborted
+	bgCtx, bgCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer bgCancel()
+
+	switch {
+	case err == nil:
+		if err := p.db.MarkSubmissionReviewed(bgCtx, submission.ID); err != nil {
+			log.Printf("Worker %d: %v", id, err)
+			return
+		}
+		log.Printf("Worker %d: submission %s reviewed", id, submission.ID)
+
+	case jobCtx.Err() != nil:
+		// Interrupted by shutdown, not the submission's fault
+		if err := p.db.ReleaseSubmission(bgCtx, submission.ID); err != nil {
+			log.Printf("Worker %d: %v", id, err)
+			return
+		}
+		log.Printf("Worker %d: submission %s released during shutdown", id, submission.ID)
+
+	case IsPermanent(err) || submission.Attempts >= p.config.MaxAttempts:
+		if err := p.db.FailSubmission(bgCtx, submission.ID, err.Error()); err != nil {
+			log.Printf("Worker %d: %v", id, err)
+			return
+		}
+		log.Printf("Worker %d: submission %s failed: %v", id, submission.ID, err)
+
+	default:
+		delay := p.backoff(submission.Attempts)
+		if err := p.db.RetrySubmission(bgCtx, submission.ID, err.Error(), delay); err != nil {
+			log.Printf("Worker %d: %v", id, err)
+			return
+		}
+		log.Printf("Worker %d: submission %s will retry in %s: %v", id, submission.ID, delay, err)
+	}
+}
+
+// backoff returns the exponential delay before the next attempt, with up to 20% jitter
+func (p *Pool) backoff(attempt int) time.Duration {
+	delay := p.config.BaseBackoff
+	for i := 1; i < attempt && delay < p.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, p.config.MaxBackoff)
+	return delay + time.Duration(rand.Int64N(int64(delay)/5+1))
+}
+
+// requeueStale returns stuck reviews to the queue, failing those out of attempts
+func (p *Pool) requeueStale(ctx context.Context) {
+	requeued, failed, err := p.db.RequeueStaleSubmissions(ctx, p.config.StaleAfter, p.config.MaxAttempts)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("Review worker pool: %v", err)
+		}
+		return
+	}
+	if requeued > 0 {
+		log.Printf("Review worker pool: requeued %d stale submissions", requeued)
+	}
+	if failed > 0 {
+		log.Printf("Review worker pool: failed %d stale submissions that ran out of attempts", failed)
+	}
+}
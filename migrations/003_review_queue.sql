@@ -0,0 +1,16 @@
+-- Review queue bookkeeping for the background worker
+-- Run this script after 002_seed_challenges.sql
+
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS attempts INTEGER DEFAULT 0;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS claimed_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE submissions ADD COLUMN IF NOT EXISTS failure_reason TEXT;
+
+-- Workers poll pending submissions ordered by age
+CREATE INDEX IF NOT EXISTS idx_submissions_pending_queue
+    ON submissions(next_attempt_at, created_at)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_submissions_reviewing
+    ON submissions(claimed_at)
+    WHERE status = 'reviewing';
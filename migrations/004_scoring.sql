@@ -0,0 +1,10 @@
+-- Per-challenge review category weights used by the scoring engine
+-- Keys are review category names (e.g. "Code Quality"), values are relative weights.
+-- NULL means the default weights apply.
+
+ALTER TABLE challenges ADD COLUMN IF NOT EXISTS category_weights JSONB;
+
+-- Speeds up best-score aggregation per user and challenge
+CREATE INDEX IF NOT EXISTS idx_submissions_user_challenge_reviewed
+    ON submissions(user_id, challenge_id, score)
+    WHERE status = 'reviewed';
@@ -0,0 +1,41 @@
+-- Materialized leaderboard snapshots, refreshed periodically by the server
+-- A snapshot is keyed by period plus an optional tech stack or difficulty filter
+-- ('' means unfiltered).
+
+CREATE TABLE IF NOT EXISTS leaderboard_snapshots (
+    period VARCHAR(20) NOT NULL,
+    tech_stack VARCHAR(100) NOT NULL DEFAULT '',
+    difficulty VARCHAR(20) NOT NULL DEFAULT '',
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rank INTEGER NOT NULL,
+    previous_rank INTEGER, -- Rank recorded the previous day, NULL if unranked then
+    total_score INTEGER NOT NULL DEFAULT 0,
+    challenges_completed INTEGER NOT NULL DEFAULT 0,
+    average_review_score DOUBLE PRECISION NOT NULL DEFAULT 0,
+    last_activity_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (period, tech_stack, difficulty, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_leaderboard_snapshots_rank
+    ON leaderboard_snapshots(period, tech_stack, difficulty, rank);
+
+-- One row per materialized filter, so an empty leaderboard is distinguishable
+-- from one that was never materialized
+CREATE TABLE IF NOT EXISTS leaderboard_snapshot_runs (
+    period VARCHAR(20) NOT NULL,
+    tech_stack VARCHAR(100) NOT NULL DEFAULT '',
+    difficulty VARCHAR(20) NOT NULL DEFAULT '',
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (period, tech_stack, difficulty)
+);
+
+-- Last rank of each user per day, used to compute rank deltas
+CREATE TABLE IF NOT EXISTS leaderboard_rank_history (
+    period VARCHAR(20) NOT NULL,
+    tech_stack VARCHAR(100) NOT NULL DEFAULT '',
+    difficulty VARCHAR(20) NOT NULL DEFAULT '',
+    user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    snapshot_date DATE NOT NULL,
+    rank INTEGER NOT NULL,
+    PRIMARY KEY (period, tech_stack, difficulty, user_id, snapshot_date)
+);
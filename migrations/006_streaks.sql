@@ -0,0 +1,8 @@
+-- Streak tracking: active days are counted in the user's own time zone
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', -- IANA name, e.g. Europe/Berlin
+    ADD COLUMN IF NOT EXISTS last_active_date DATE; -- Local date of the latest day counted toward the streak
+
+-- Finds streaks that may have been broken by the daily reset job
+CREATE INDEX IF NOT EXISTS idx_users_active_streaks ON users(last_active_date) WHERE current_streak > 0;
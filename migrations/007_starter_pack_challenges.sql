@@ -0,0 +1,15 @@
+-- Ordered challenges of a starter pack and the user's progress on each
+
+CREATE TABLE IF NOT EXISTS starter_pack_challenges (
+    id VARCHAR(255) PRIMARY KEY,
+    starter_pack_id VARCHAR(255) NOT NULL REFERENCES starter_packs(id) ON DELETE CASCADE,
+    challenge_id VARCHAR(255) NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    order_index INTEGER NOT NULL DEFAULT 0,
+    is_completed BOOLEAN NOT NULL DEFAULT FALSE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    score INTEGER NOT NULL DEFAULT 0,
+    UNIQUE (starter_pack_id, challenge_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_starter_pack_challenges_pack ON starter_pack_challenges(starter_pack_id, order_index);
+CREATE INDEX IF NOT EXISTS idx_starter_pack_challenges_challenge ON starter_pack_challenges(challenge_id);
@@ -0,0 +1,10 @@
+-- Keep a history of starter packs: a user has at most one active pack, while
+-- archived packs keep their challenges and progress
+
+UPDATE starter_packs SET is_active = TRUE WHERE is_active IS NULL;
+ALTER TABLE starter_packs ALTER COLUMN is_active SET NOT NULL;
+
+ALTER TABLE starter_packs DROP CONSTRAINT IF EXISTS starter_packs_user_id_key;
+ALTER TABLE starter_packs ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_starter_packs_active_user ON starter_packs(user_id) WHERE is_active;
@@ -0,0 +1,4 @@
+-- Users can be provisioned from a session token before the Clerk user.created
+-- webhook arrives; the token may not carry an email, so it is filled in later
+
+ALTER TABLE users ALTER COLUMN email DROP NOT NULL;
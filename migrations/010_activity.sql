@@ -0,0 +1,3 @@
+-- Activity calendar and timeline: per-user lookups by time
+
+CREATE INDEX IF NOT EXISTS idx_submissions_user_created ON submissions(user_id, created_at);
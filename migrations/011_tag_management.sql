@@ -0,0 +1,3 @@
+-- Tags are managed through the admin API: names are unique regardless of case
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower ON tags(lower(name));
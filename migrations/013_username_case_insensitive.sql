@@ -0,0 +1,29 @@
+-- Usernames are unique regardless of case, matching how profiles are looked up.
+-- Case-only duplicates from before are cleared, keeping the oldest account's name.
+-- Each cleared row is reported so its owner can be asked to pick a new username.
+
+CREATE TEMP TABLE username_case_duplicates AS
+SELECT id, clerk_user_id, username FROM (
+    SELECT id, clerk_user_id, username,
+        ROW_NUMBER() OVER (PARTITION BY lower(username) ORDER BY created_at NULLS LAST, id) AS n
+    FROM users
+    WHERE username IS NOT NULL
+) ranked
+WHERE n > 1;
+
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN SELECT * FROM username_case_duplicates ORDER BY lower(username), id LOOP
+        RAISE WARNING 'Clearing username "%" of user % (clerk_user_id %), it duplicates another username ignoring case',
+            r.username, r.id, r.clerk_user_id;
+    END LOOP;
+END $$;
+
+UPDATE users SET username = NULL, updated_at = NOW()
+WHERE id IN (SELECT id FROM username_case_duplicates);
+
+DROP TABLE username_case_duplicates;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username_lower ON users(lower(username));
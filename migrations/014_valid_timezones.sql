@@ -0,0 +1,33 @@
+-- Streak queries convert NOW() into each user's time zone, so a single name
+-- Postgres does not know fails every leaderboard and profile query. Unknown names
+-- are reset to UTC and rejected from now on.
+
+DO $$
+DECLARE
+    r RECORD;
+BEGIN
+    FOR r IN
+        SELECT id, timezone FROM users
+        WHERE timezone NOT IN (SELECT name FROM pg_timezone_names)
+    LOOP
+        RAISE NOTICE 'Resetting unknown time zone % of user % to UTC', r.timezone, r.id;
+    END LOOP;
+END $$;
+
+UPDATE users SET timezone = 'UTC', updated_at = NOW()
+WHERE timezone NOT IN (SELECT name FROM pg_timezone_names);
+
+CREATE OR REPLACE FUNCTION users_check_timezone() RETURNS trigger AS $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_timezone_names WHERE name = NEW.timezone) THEN
+        RAISE EXCEPTION 'unknown time zone "%"', NEW.timezone
+            USING ERRCODE = 'check_violation', CONSTRAINT = 'users_timezone_known';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS users_timezone_known ON users;
+CREATE TRIGGER users_timezone_known
+    BEFORE INSERT OR UPDATE OF timezone ON users
+    FOR EACH ROW EXECUTE FUNCTION users_check_timezone();
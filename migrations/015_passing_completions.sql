@@ -0,0 +1,16 @@
+-- challenges_completed only counts challenges with a passing submission: one
+-- scoring at least 70% (scoring.PassPercent) of the challenge's max score.
+-- Recompute it for users whose totals were written under the old rule.
+
+UPDATE users u SET challenges_completed = COALESCE(done.n, 0), updated_at = NOW()
+FROM users u2
+LEFT JOIN (
+    SELECT s.user_id, COUNT(DISTINCT s.challenge_id) AS n
+    FROM submissions s
+    JOIN challenges c ON c.id = s.challenge_id
+    WHERE s.status = 'reviewed'
+        AND COALESCE(c.max_score, 100) > 0
+        AND COALESCE(s.score, 0) * 100 >= 70 * COALESCE(c.max_score, 100)
+    GROUP BY s.user_id
+) done ON done.user_id = u2.id
+WHERE u.id = u2.id AND COALESCE(u.challenges_completed, 0) <> COALESCE(done.n, 0);